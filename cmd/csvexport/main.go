@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/p2pquake/web-client/export"
+	"github.com/p2pquake/web-client/repository"
+)
+
+// 地震情報を CSV で標準出力に書き出す。
+// 接続先は Web サーバと同じ環境変数（MONGODB_URL, DATABASE, COLLECTION, FIXTURES）で指定する
+func main() {
+	if err := run(); err != nil {
+		log.Fatalf("Export error: %v\n", err)
+	}
+}
+
+func run() error {
+	q := url.Values{}
+	params := []struct{ name, usage string }{
+		{"from", "開始日 (YYYY-MM-DD)"},
+		{"to", "終了日 (YYYY-MM-DD, この日を含む)"},
+		{"min-scale", "最大震度の下限 (震度コード 10〜70)"},
+		{"max-scale", "最大震度の上限 (震度コード 10〜70)"},
+		{"min-mag", "マグニチュードの下限"},
+		{"max-mag", "マグニチュードの上限"},
+		{"min-depth", "深さの下限 (km)"},
+		{"max-depth", "深さの上限 (km)"},
+		{"hypocenter", "震源名 (部分一致)"},
+		{"pref", "震度を観測した都道府県 (複数指定可)"},
+		{"type", "情報の種類 (ScalePrompt など, 複数指定可)"},
+	}
+	for _, p := range params {
+		flag.Var(queryFlag{q, strings.ReplaceAll(p.name, "-", "_")}, p.name, p.usage)
+	}
+	bom := flag.Bool("bom", false, "先頭に BOM を付ける")
+	flag.Parse()
+
+	// /export/earthquakes.csv と同じ条件で読み取る
+	filter, page, err := repository.ParseEarthquakeQuery(q)
+	if err != nil {
+		return err
+	}
+
+	repo, closeRepo, err := repository.FromEnv()
+	if err != nil {
+		return err
+	}
+	defer closeRepo()
+
+	w := bufio.NewWriter(os.Stdout)
+	if err := export.WriteEarthquakes(context.Background(), repo, filter, page, w, *bom, nil); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// フラグの値をクエリパラメータとして追加する
+type queryFlag struct {
+	q   url.Values
+	key string
+}
+
+func (f queryFlag) String() string {
+	if f.q == nil {
+		return ""
+	}
+	return strings.Join(f.q[f.key], ",")
+}
+
+func (f queryFlag) Set(v string) error {
+	f.q.Add(f.key, v)
+	return nil
+}
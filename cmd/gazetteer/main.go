@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+// 震度観測点の市区町村の座標（model/data/gazetteer.json）を標準出力に書き出す。
+//
+// 引数は総務省「全国地方公共団体コード」を CSV（UTF-8）にしたもの。政令指定都市の区のシートも別の CSV として渡す。
+// 座標は国土数値情報「市区町村役場データ（P34）」の GeoJSON から本庁舎の位置を使う。
+//
+//	go run ./cmd/gazetteer -offices P34-14.geojson codes.csv wards.csv > model/data/gazetteer.json
+func main() {
+	offices := flag.String("offices", "", "国土数値情報 P34 の GeoJSON（複数はカンマ区切り）")
+	flag.Parse()
+	if *offices == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	coords := make(map[string][2]float64)
+	for _, path := range strings.Split(*offices, ",") {
+		if err := readOffices(path, coords); err != nil {
+			log.Fatalf("Offices error: %v\n", err)
+		}
+	}
+
+	var places []model.Place
+	for _, path := range flag.Args() {
+		p, err := readCodes(path)
+		if err != nil {
+			log.Fatalf("Codes error: %v\n", err)
+		}
+		places = append(places, p...)
+	}
+
+	var result []model.Place
+	seen := make(map[string]bool)
+	for _, p := range places {
+		c, ok := coords[p.Code]
+		if !ok || seen[p.Code] {
+			if !ok {
+				log.Printf("No office: %s %s %s\n", p.Code, p.Pref, p.Name)
+			}
+			continue
+		}
+		seen[p.Code] = true
+		p.Latitude, p.Longitude = round(c[1]), round(c[0])
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+
+	w := bufio.NewWriter(os.Stdout)
+	if err := write(w, result); err != nil {
+		log.Fatalf("Write error: %v\n", err)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("Write error: %v\n", err)
+	}
+}
+
+// 団体コード（6 桁、末尾は検査数字）
+var codePattern = regexp.MustCompile(`^\d{6}$`)
+
+// 政令指定都市の区（「札幌市中央区」）
+var wardPattern = regexp.MustCompile(`^(.+?)市(.+区)$`)
+
+// 団体コード・都道府県名・市区町村名の列から、気象庁の震度観測点と同じ書き方の名前を作る
+func readCodes(path string) ([]model.Place, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	var places []model.Place
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			continue
+		}
+		code := strings.TrimPrefix(strings.TrimSpace(record[0]), "\ufeff")
+		pref, name := strings.TrimSpace(record[1]), strings.TrimSpace(record[2])
+		// 見出しと都道府県の行
+		if !codePattern.MatchString(code) || name == "" {
+			continue
+		}
+		places = append(places, model.Place{Pref: pref, Name: jmaName(pref, code[:5], name), Code: code[:5]})
+	}
+	return places, nil
+}
+
+// 政令指定都市の区は「市」を除き（札幌中央区）、東京の特別区は「東京」を付ける（東京千代田区）。
+// 堺市の区だけは「大阪堺市堺区」と書く
+func jmaName(pref, code, name string) string {
+	if pref == "東京都" && strings.HasPrefix(code, "131") {
+		return "東京" + name
+	}
+	if strings.HasPrefix(name, "堺市") && strings.HasSuffix(name, "区") {
+		return "大阪" + name
+	}
+	if m := wardPattern.FindStringSubmatch(name); m != nil {
+		return m[1] + m[2]
+	}
+	return name
+}
+
+type featureCollection struct {
+	Features []struct {
+		Properties map[string]interface{} `json:"properties"`
+		Geometry   struct {
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// P34_001 が行政区域コード、P34_002 が施設分類（1 が本庁舎）
+func readOffices(path string, coords map[string][2]float64) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var fc featureCollection
+	if err := json.Unmarshal(b, &fc); err != nil {
+		return err
+	}
+
+	for _, f := range fc.Features {
+		code := fmt.Sprint(f.Properties["P34_001"])
+		if fmt.Sprint(f.Properties["P34_002"]) != "1" || len(f.Geometry.Coordinates) < 2 {
+			continue
+		}
+		coords[code] = [2]float64{f.Geometry.Coordinates[0], f.Geometry.Coordinates[1]}
+	}
+	return nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
+
+// 1 行に 1 件ずつ書き出す（差分を読みやすくするため）
+func write(w io.Writer, places []model.Place) error {
+	if _, err := io.WriteString(w, "[\n"); err != nil {
+		return err
+	}
+	for i, p := range places {
+		b, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		sep := ",\n"
+		if i == len(places)-1 {
+			sep = "\n"
+		}
+		if _, err := fmt.Fprintf(w, "%s%s", b, sep); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
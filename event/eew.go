@@ -0,0 +1,98 @@
+package event
+
+import (
+	"github.com/p2pquake/web-client/model"
+)
+
+// 緊急地震速報（警報）で予想した都道府県と、実際に震度5弱以上を観測した都道府県
+type EEWResult struct {
+	Earthquakes []*model.Earthquake // 続いて発表された地震情報（受信順）
+	Observed    bool                // 震度の情報があるか
+	Cancelled   bool                // 最後の緊急地震速報（警報）が取消か
+	Prefs       []PrefResult        // 都道府県コード順
+}
+
+type PrefResult struct {
+	Pref         string
+	Warned       bool
+	MaxScale     string // 観測がなければ空
+	MaxScaleCode int
+}
+
+// 震度5弱以上（「5弱以上と推定」を含む）を観測したか
+func (p PrefResult) Strong() bool {
+	return p.MaxScaleCode >= 45
+}
+
+func (p PrefResult) Outcome() string {
+	switch {
+	case p.Warned && p.Strong():
+		return "的中"
+	case p.Warned:
+		return "空振り"
+	case p.Strong():
+		return "見逃し"
+	}
+	return ""
+}
+
+// e に含まれる緊急地震速報（警報）と地震情報を突き合わせる。地震情報がなければ nil
+func VerifyEEW(e *Event) *EEWResult {
+	result := &EEWResult{}
+	warned := make(map[string]bool)
+	var detail, prompt *model.Earthquake
+	for _, m := range e.Members {
+		switch v := m.Data.(type) {
+		case *model.EEW:
+			// 取り消された場合はそれまでの警報をなかったものとする
+			result.Cancelled = v.Cancelled
+			if v.Cancelled {
+				warned = make(map[string]bool)
+				continue
+			}
+			for _, area := range v.Areas {
+				warned[model.NormalizePref(area)] = true
+			}
+		case *model.Earthquake:
+			result.Earthquakes = append(result.Earthquakes, v)
+			switch v.IssueType {
+			case "DetailScale":
+				detail = v
+			case "ScalePrompt":
+				prompt = v
+			}
+		}
+	}
+	if len(result.Earthquakes) == 0 {
+		return nil
+	}
+
+	// 最新の各地の震度（なければ震度速報）
+	observed := detail
+	if observed == nil {
+		observed = prompt
+	}
+	scales := make(map[string]model.PointsByScale)
+	if observed != nil {
+		result.Observed = true
+		for _, p := range observed.Points {
+			for _, s := range p.Points {
+				if cur, ok := scales[p.Pref]; !ok || model.ScaleRank(s.ScaleCode) > model.ScaleRank(cur.ScaleCode) {
+					scales[p.Pref] = s
+				}
+			}
+		}
+	}
+
+	for _, pref := range model.Prefectures {
+		s, ok := scales[pref]
+		r := PrefResult{Pref: pref, Warned: warned[pref]}
+		if ok {
+			r.MaxScale, r.MaxScaleCode = s.Scale, s.ScaleCode
+		}
+		if r.Warned || r.Strong() {
+			result.Prefs = append(result.Prefs, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package event
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+func members(data ...interface{}) *Event {
+	e := &Event{}
+	for _, d := range data {
+		e.Members = append(e.Members, Member{Data: d})
+	}
+	return e
+}
+
+func observed(issueType string, points ...model.PointsByPref) *model.Earthquake {
+	return &model.Earthquake{IssueType: issueType, Points: points}
+}
+
+func scaleAt(pref string, codes ...int) model.PointsByPref {
+	p := model.PointsByPref{Pref: pref}
+	for _, c := range codes {
+		p.Points = append(p.Points, model.PointsByScale{Scale: model.ScaleName(c), ScaleCode: c})
+	}
+	return p
+}
+
+func TestVerifyEEW(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    *Event
+		want     []PrefResult
+		observed bool
+	}{
+		{
+			name: "hit, miss and false alarm",
+			event: members(
+				&model.EEW{Areas: []string{"石川", "富山"}},
+				observed("ScalePrompt", scaleAt("石川県", 60)),
+				observed("DetailScale", scaleAt("石川県", 30, 60), scaleAt("新潟県", 46), scaleAt("富山県", 40)),
+			),
+			want: []PrefResult{
+				{Pref: "新潟県", MaxScale: "5弱以上と推定", MaxScaleCode: 46},
+				{Pref: "富山県", Warned: true, MaxScale: "4", MaxScaleCode: 40},
+				{Pref: "石川県", Warned: true, MaxScale: "6強", MaxScaleCode: 60},
+			},
+			observed: true,
+		},
+		{
+			// 各地の震度がまだなければ震度速報で判定する
+			name: "scale prompt",
+			event: members(
+				&model.EEW{Areas: []string{"石川"}},
+				observed("ScalePrompt", scaleAt("石川県", 50)),
+			),
+			want:     []PrefResult{{Pref: "石川県", Warned: true, MaxScale: "5強", MaxScaleCode: 50}},
+			observed: true,
+		},
+		{
+			// 震源に関する情報だけなら観測なし
+			name: "no scale",
+			event: members(
+				&model.EEW{Areas: []string{"石川"}},
+				observed("Destination"),
+			),
+			want: []PrefResult{{Pref: "石川県", Warned: true}},
+		},
+		{
+			// 取り消された警報は数えない
+			name: "cancelled",
+			event: members(
+				&model.EEW{Areas: []string{"石川"}},
+				&model.EEW{Cancelled: true},
+				&model.EEW{Areas: []string{"富山"}},
+				observed("DetailScale", scaleAt("富山県", 20)),
+			),
+			want:     []PrefResult{{Pref: "富山県", Warned: true, MaxScale: "2", MaxScaleCode: 20}},
+			observed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := VerifyEEW(tt.event)
+			if r == nil {
+				t.Fatal("VerifyEEW() = nil")
+			}
+			if !reflect.DeepEqual(r.Prefs, tt.want) {
+				t.Errorf("Prefs = %+v, want %+v", r.Prefs, tt.want)
+			}
+			if r.Observed != tt.observed {
+				t.Errorf("Observed = %v, want %v", r.Observed, tt.observed)
+			}
+		})
+	}
+
+	// 地震情報がなければ検証しない
+	if r := VerifyEEW(members(&model.EEW{Areas: []string{"石川"}})); r != nil {
+		t.Errorf("VerifyEEW() without earthquakes = %+v, want nil", r)
+	}
+
+	// 最後が取消なら Cancelled
+	if r := VerifyEEW(members(&model.EEW{Areas: []string{"石川"}}, &model.EEW{Cancelled: true}, observed("ScalePrompt"))); !r.Cancelled || len(r.Prefs) != 0 {
+		t.Errorf("VerifyEEW() after cancellation = %+v", r)
+	}
+}
+
+func TestPrefResultOutcome(t *testing.T) {
+	tests := []struct {
+		r    PrefResult
+		want string
+	}{
+		{r: PrefResult{Warned: true, MaxScaleCode: 45}, want: "的中"},
+		{r: PrefResult{Warned: true, MaxScaleCode: 46}, want: "的中"},
+		{r: PrefResult{Warned: true, MaxScaleCode: 40}, want: "空振り"},
+		{r: PrefResult{MaxScaleCode: 50}, want: "見逃し"},
+		{r: PrefResult{MaxScaleCode: 40}, want: ""},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Outcome(); got != tt.want {
+			t.Errorf("%+v.Outcome() = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package event
+
+import (
+	"sort"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// 同じ地震とみなす発生時刻の差
+	originTolerance = 60 * time.Second
+	// 震源が分かっている場合に同じ地震とみなす距離（km）
+	hypocenterTolerance = 150.0
+	// 地震の発生からこの時間内の津波情報をまとめる
+	tsunamiWindow = 2 * time.Hour
+	// 地震感知情報の開始時刻の範囲（発生時刻から）
+	userquakeBefore = 30 * time.Second
+	userquakeAfter  = 3 * time.Minute
+)
+
+// 候補を探す範囲（基準にした情報の受信時刻の前後）
+const SearchWindow = tsunamiWindow + 10*time.Minute
+
+// ひとつの地震に関する情報のまとまり
+type Event struct {
+	OriginTime *time.Time       // 分からなければ nil
+	Hypocenter model.Hypocenter // 分からなければ緯度・経度が -200
+	Members    []Member         // 受信順
+}
+
+type Member struct {
+	Item bson.M
+	Data interface{}
+}
+
+// anchor と同じ地震の情報を candidates から集める。anchor 自身は必ず含む
+func Group(anchor bson.M, candidates []bson.M) (*Event, error) {
+	data, err := model.Convert(anchor)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []Member
+	for _, c := range candidates {
+		if d, err := model.Convert(c); err == nil {
+			members = append(members, Member{Item: c, Data: d})
+		}
+	}
+
+	e := &Event{}
+	e.OriginTime, e.Hypocenter = reference(data, members)
+
+	result := []Member{{Item: anchor, Data: data}}
+	anchorID := anchor["_id"]
+	for _, m := range members {
+		if m.Item["_id"] == anchorID {
+			continue
+		}
+		if e.OriginTime != nil && e.contains(m.Data) {
+			result = append(result, m)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		ti, tj := ItemTime(result[i].Item), ItemTime(result[j].Item)
+		if ti != tj {
+			return ti < tj
+		}
+		return objectID(result[i].Item).Hex() < objectID(result[j].Item).Hex()
+	})
+	e.Members = result
+	return e, nil
+}
+
+// 発生時刻と震源。地震情報・緊急地震速報以外は、対応する地震情報から求める
+func reference(data interface{}, members []Member) (*time.Time, model.Hypocenter) {
+	var origin *time.Time
+	hypocenter := model.Hypocenter{Latitude: -200, Longitude: -200}
+
+	switch v := data.(type) {
+	case *model.Earthquake:
+		origin, hypocenter = v.OccurredAt, v.HypocenterDetail
+	case *model.EEW:
+		origin, hypocenter = v.OccurredAt, v.HypocenterDetail
+		// 速報の発生時刻は後の地震情報とずれることがあるため、地震情報があればそちらに揃える
+		if origin != nil {
+			if o := nearestOrigin(members, origin.Add(-originTolerance), origin.Add(originTolerance), *origin); o != nil {
+				origin = o
+			}
+		}
+	case *model.Userquake:
+		if v.StartedAt != nil {
+			origin = nearestOrigin(members, v.StartedAt.Add(-userquakeAfter), v.StartedAt.Add(userquakeBefore), *v.StartedAt)
+		}
+	case *model.Tsunami:
+		if v.IssuedAt != nil {
+			origin = nearestOrigin(members, v.IssuedAt.Add(-tsunamiWindow), *v.IssuedAt, *v.IssuedAt)
+		}
+	}
+	if origin == nil {
+		return nil, hypocenter
+	}
+
+	// 震度速報には震源がないため、同じ発生時刻の震源情報などから補う
+	if !known(hypocenter) {
+		for _, m := range members {
+			if eq, ok := m.Data.(*model.Earthquake); ok && near(eq.OccurredAt, *origin, originTolerance) && known(eq.HypocenterDetail) {
+				hypocenter = eq.HypocenterDetail
+				break
+			}
+		}
+	}
+	return origin, hypocenter
+}
+
+// from〜to に発生した地震情報のうち、発生時刻が base に最も近いもの
+func nearestOrigin(members []Member, from, to, base time.Time) *time.Time {
+	var origin *time.Time
+	for _, m := range members {
+		eq, ok := m.Data.(*model.Earthquake)
+		if !ok || eq.OccurredAt == nil || eq.OccurredAt.Before(from) || eq.OccurredAt.After(to) {
+			continue
+		}
+		if origin == nil || abs(eq.OccurredAt.Sub(base)) < abs(origin.Sub(base)) {
+			origin = eq.OccurredAt
+		}
+	}
+	return origin
+}
+
+func (e *Event) contains(data interface{}) bool {
+	origin := *e.OriginTime
+	switch v := data.(type) {
+	case *model.Earthquake:
+		return near(v.OccurredAt, origin, originTolerance) && e.nearHypocenter(v.HypocenterDetail)
+	case *model.EEW:
+		return near(v.OccurredAt, origin, originTolerance) && e.nearHypocenter(v.HypocenterDetail)
+	case *model.Userquake:
+		return v.StartedAt != nil && !v.StartedAt.Before(origin.Add(-userquakeBefore)) && !v.StartedAt.After(origin.Add(userquakeAfter))
+	case *model.Tsunami:
+		return v.IssuedAt != nil && !v.IssuedAt.Before(origin) && !v.IssuedAt.After(origin.Add(tsunamiWindow))
+	}
+	return false
+}
+
+// どちらかの震源が分からなければ近いものとみなす
+func (e *Event) nearHypocenter(h model.Hypocenter) bool {
+	if !known(e.Hypocenter) || !known(h) {
+		return true
+	}
+	return model.Distance(e.Hypocenter.Latitude, e.Hypocenter.Longitude, h.Latitude, h.Longitude) <= hypocenterTolerance
+}
+
+// 並び替えに使う受信時刻。地震感知情報は開始時刻
+func ItemTime(item bson.M) string {
+	if startedAt, ok := item["started_at"].(string); ok {
+		return startedAt
+	}
+	t, _ := item["time"].(string)
+	return t
+}
+
+func objectID(item bson.M) primitive.ObjectID {
+	id, _ := item["_id"].(primitive.ObjectID)
+	return id
+}
+
+func known(h model.Hypocenter) bool {
+	return -90 <= h.Latitude && h.Latitude <= 90 && -180 <= h.Longitude && h.Longitude <= 180
+}
+
+func near(t *time.Time, base time.Time, tolerance time.Duration) bool {
+	return t != nil && abs(t.Sub(base)) <= tolerance
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,96 @@
+package event
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func oid(n byte) primitive.ObjectID {
+	var id primitive.ObjectID
+	id[len(id)-1] = n
+	return id
+}
+
+func earthquakeItem(n byte, received, issueType, origin string, lat, lon float64) bson.M {
+	return bson.M{
+		"_id":   oid(n),
+		"code":  551,
+		"time":  received,
+		"issue": bson.M{"type": issueType, "time": received[:19]},
+		"earthquake": bson.M{
+			"time":       origin,
+			"maxScale":   30,
+			"hypocenter": bson.M{"name": "", "magnitude": -1, "depth": -1, "latitude": lat, "longitude": lon},
+		},
+	}
+}
+
+func TestGroup(t *testing.T) {
+	// 2026/10/01 09:58:00 に能登半島沖で発生した地震
+	items := map[byte]bson.M{
+		1: earthquakeItem(1, "2026/10/01 10:05:00.000", "DetailScale", "2026/10/01 09:58:00", 37.5, 137.2),
+		2: earthquakeItem(2, "2026/10/01 10:00:00.000", "ScalePrompt", "2026/10/01 09:58:00", -200, -200),
+		3: {
+			"_id": oid(3), "code": 556, "time": "2026/10/01 09:58:12.000",
+			"issue":      bson.M{"time": "2026/10/01 09:58:12", "serial": "1"},
+			"earthquake": bson.M{"originTime": "2026/10/01 09:57:55", "hypocenter": bson.M{"latitude": 37.4, "longitude": 137.1}},
+			"areas":      bson.A{bson.M{"pref": "石川", "name": "石川県能登"}},
+		},
+		// 10 分後の別の地震
+		4: earthquakeItem(4, "2026/10/01 10:12:00.000", "DetailScale", "2026/10/01 10:08:00", 37.5, 137.2),
+		// ほぼ同時刻だが遠く離れた地震。震源の分からない震度速報・地震感知情報・津波情報はどちらにも含まれる
+		5: earthquakeItem(5, "2026/10/01 10:03:00.000", "DetailScale", "2026/10/01 09:58:20", 31.5, 130.5),
+		6: {"_id": oid(6), "code": 9611, "time": "2026/10/01 09:58:30.000", "started_at": "2026/10/01 09:58:10.000", "updated_at": "2026/10/01 09:58:30.000"},
+		7: {"_id": oid(7), "code": 9611, "time": "2026/10/01 10:02:30.000", "started_at": "2026/10/01 10:02:00.000", "updated_at": "2026/10/01 10:02:30.000"},
+		8: {"_id": oid(8), "code": 552, "time": "2026/10/01 10:03:00.000", "issue": bson.M{"time": "2026/10/01 10:03:00", "type": "Focus"}},
+		9: {"_id": oid(9), "code": 552, "time": "2026/10/01 12:30:00.000", "issue": bson.M{"time": "2026/10/01 12:30:00", "type": "Focus"}},
+	}
+	var candidates []bson.M
+	for n := byte(1); n <= 9; n++ {
+		candidates = append(candidates, items[n])
+	}
+
+	tests := []struct {
+		name   string
+		anchor byte
+		want   []byte
+	}{
+		{name: "detail scale", anchor: 1, want: []byte{6, 3, 2, 8, 1}},
+		{name: "scale prompt", anchor: 2, want: []byte{6, 3, 2, 8, 1}},
+		{name: "eew", anchor: 3, want: []byte{6, 3, 2, 8, 1}},
+		{name: "userquake", anchor: 6, want: []byte{6, 3, 2, 8, 1}},
+		{name: "other earthquake", anchor: 4, want: []byte{4}},
+		{name: "far earthquake", anchor: 5, want: []byte{6, 2, 5, 8}},
+		{name: "late userquake", anchor: 7, want: []byte{7}},
+		{name: "late tsunami", anchor: 9, want: []byte{9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Group(items[tt.anchor], candidates)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []byte
+			for _, m := range e.Members {
+				id := objectID(m.Item)
+				got = append(got, id[len(id)-1])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Group() members = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 震度速報の震源は震源・震度情報から補う
+	e, err := Group(items[2], candidates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Hypocenter.Latitude != 37.5 || e.Hypocenter.Longitude != 137.2 {
+		t.Errorf("Group() hypocenter = %+v, want 37.5, 137.2", e.Hypocenter)
+	}
+}
@@ -0,0 +1,44 @@
+package event
+
+import (
+	"github.com/p2pquake/web-client/model"
+)
+
+// 同じ地震の前回の地震情報からの変化
+type Revision struct {
+	Previous *model.Earthquake
+	Changes  []model.RevisionChange
+	// 市区町村ごとの震度がある前回の地震情報と比べる（震源情報には震度がなく、震度速報は地域ごとのため）
+	PointsBase *model.Earthquake
+	Points     []model.PointChange
+}
+
+// e のうち current の直前に受信した地震情報との差分。直前のものがなければ nil
+func Revise(e *Event, current *model.Earthquake) *Revision {
+	var prev, base *model.Earthquake
+	found := false
+	for _, m := range e.Members {
+		eq, ok := m.Data.(*model.Earthquake)
+		if !ok {
+			continue
+		}
+		if eq.ObjectID == current.ObjectID {
+			found = true
+			break
+		}
+		prev = eq
+		if eq.HasCityPoints() {
+			base = eq
+		}
+	}
+	if !found || prev == nil {
+		return nil
+	}
+
+	r := &Revision{Previous: prev, Changes: model.DiffEarthquake(prev, current)}
+	if base != nil && current.HasCityPoints() {
+		r.PointsBase = base
+		r.Points = model.DiffPoints(base, current)
+	}
+	return r
+}
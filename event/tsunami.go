@@ -0,0 +1,74 @@
+package event
+
+import (
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// 続けて発表された津波情報とみなす間隔
+	tsunamiSequenceGap = 24 * time.Hour
+	// 一連の津波情報を探す範囲（基準にした情報の受信時刻の前後）
+	TsunamiSearchWindow = 2 * tsunamiSequenceGap
+)
+
+type TsunamiStep struct {
+	Item    bson.M
+	Tsunami *model.Tsunami
+}
+
+// anchor を含む一連の津波情報（受信順）。解除の情報までをひと続きとする
+func TsunamiSequence(anchor bson.M, candidates []bson.M) []TsunamiStep {
+	items := []bson.M{anchor}
+	for _, c := range candidates {
+		if objectID(c) != objectID(anchor) {
+			items = append(items, c)
+		}
+	}
+
+	// 古い順
+	repository.SortNewest(items)
+	var steps []TsunamiStep
+	current := -1
+	for i := len(items) - 1; i >= 0; i-- {
+		t, ok := convertTsunami(items[i])
+		if !ok {
+			continue
+		}
+		if objectID(items[i]) == objectID(anchor) {
+			current = len(steps)
+		}
+		steps = append(steps, TsunamiStep{Item: items[i], Tsunami: t})
+	}
+	if current < 0 {
+		return nil
+	}
+
+	first := current
+	for first > 0 && !steps[first-1].Tsunami.Cancelled && continued(steps[first-1], steps[first]) {
+		first--
+	}
+	last := current
+	for last+1 < len(steps) && !steps[last].Tsunami.Cancelled && continued(steps[last], steps[last+1]) {
+		last++
+	}
+	return steps[first : last+1]
+}
+
+func convertTsunami(item bson.M) (*model.Tsunami, bool) {
+	data, err := model.Convert(item)
+	if err != nil {
+		return nil, false
+	}
+	t, ok := data.(*model.Tsunami)
+	return t, ok
+}
+
+func continued(prev, next TsunamiStep) bool {
+	p := model.ParseTime(ItemTime(prev.Item))
+	n := model.ParseTime(ItemTime(next.Item))
+	return p != nil && n != nil && n.Sub(*p) <= tsunamiSequenceGap
+}
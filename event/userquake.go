@@ -0,0 +1,195 @@
+package event
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// 地震感知情報の開始時刻と発生時刻の差の許容範囲（開始時刻 - 発生時刻）
+	matchBefore = -30 * time.Second
+	matchAfter  = 2 * time.Minute
+	// 地震情報の受信時刻を探す範囲（開始時刻から）
+	matchSearchBefore = 3 * time.Minute
+	matchSearchAfter  = 30 * time.Minute
+	// これより低い一致度は対応なしとする
+	minMatchScore = 0.4
+	// 結果が変わらなくなるまでの経過時間（後から地震情報が届くことがある）
+	settleAfter = time.Hour
+)
+
+// 地震感知情報と地震情報（551）の対応を調べて、地震感知情報に保存する
+type Correlator struct {
+	Repository repository.Repository
+}
+
+func NewCorrelator(repo repository.Repository) *Correlator {
+	return &Correlator{Repository: repo}
+}
+
+// items のうち地震感知情報に対応する地震情報を調べて保存し、items にも反映する（match フィールド）。
+// 落ち着いた結果が保存済みなら調べ直さない。候補の地震情報は items 全体の期間についてまとめて読み込む。
+// c が nil なら何もしない。調べられなかったものはそのままにする
+func (c *Correlator) Annotate(ctx context.Context, items []bson.M) {
+	if c == nil {
+		return
+	}
+
+	type pending struct {
+		item    bson.M
+		id      primitive.ObjectID
+		started time.Time
+	}
+	var targets []pending
+	var first, last time.Time
+	for _, item := range items {
+		// started_at があるのは地震感知情報だけ
+		startedAt, ok := item["started_at"].(string)
+		if !ok {
+			continue
+		}
+		started := model.ParseTime(startedAt)
+		if started == nil {
+			continue
+		}
+		id, ok := item["_id"].(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		if match, ok := item["match"].(bson.M); ok && match["settled"] == true {
+			continue
+		}
+
+		if len(targets) == 0 || started.Before(first) {
+			first = *started
+		}
+		if len(targets) == 0 || started.After(last) {
+			last = *started
+		}
+		targets = append(targets, pending{item: item, id: id, started: *started})
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	candidates, err := c.Repository.FindJmas(ctx, repository.Page{
+		Since: first.Add(-matchSearchBefore).Format("2006/01/02 15:04:05"),
+		Until: last.Add(matchSearchAfter).Format("2006/01/02 15:04:05"),
+		Codes: []int{551},
+	})
+	if err != nil {
+		log.Printf("Correlate error: %v\n", err)
+		return
+	}
+	earthquakes := convertCandidates(candidates)
+
+	for _, t := range targets {
+		uq, err := model.ToUserquake(t.item)
+		if err != nil {
+			continue
+		}
+		record := model.UserquakeMatchRecord{Settled: time.Since(t.started) > settleAfter}
+		if m := bestMatch(t.started, uq, earthquakes); m != nil {
+			record.Found, record.Match = true, *m
+		}
+
+		// 保存できなくても今回の表示には使う
+		if err := c.Repository.SaveUserquakeMatch(ctx, t.id, record); err != nil {
+			log.Printf("Correlate error: %v\n", err)
+		}
+		setMatch(t.item, record)
+	}
+}
+
+// 保存したものと同じ形で item に反映する
+func setMatch(item bson.M, record model.UserquakeMatchRecord) {
+	bytes, _ := bson.Marshal(record)
+	var doc bson.M
+	bson.Unmarshal(bytes, &doc)
+	item["match"] = doc
+}
+
+// 震度速報・各地の震度に関する情報のみ（受信の古い順）
+func convertCandidates(candidates []bson.M) []*model.Earthquake {
+	repository.SortNewest(candidates)
+	var earthquakes []*model.Earthquake
+	for i := len(candidates) - 1; i >= 0; i-- {
+		eq, err := model.ToEarthquake(candidates[i])
+		if err != nil || eq.OccurredAt == nil {
+			continue
+		}
+		if eq.IssueType != "ScalePrompt" && eq.IssueType != "DetailScale" {
+			continue
+		}
+		earthquakes = append(earthquakes, eq)
+	}
+	return earthquakes
+}
+
+// 一致度のもっとも高い地震情報。同じなら後に受信したもの（earthquakes は受信の古い順）。
+// 一致度は発生時刻の差と、揺れを感じた地域と震度を観測した地域の重なりから求める。
+// 地震情報の地域（震度速報の地域・各地の震度の市区町村）と地震感知情報の地域は区分が異なるため、
+// 地域の重なりは都道府県単位で比べる
+func bestMatch(started time.Time, uq *model.Userquake, earthquakes []*model.Earthquake) *model.UserquakeMatch {
+	prefs := userquakePrefs(uq)
+	if len(prefs) == 0 {
+		return nil
+	}
+
+	var best *model.UserquakeMatch
+	for _, eq := range earthquakes {
+		dt := started.Sub(*eq.OccurredAt)
+		if dt < matchBefore || dt > matchAfter {
+			continue
+		}
+		overlap := prefOverlap(prefs, eq)
+		if overlap == 0 {
+			continue
+		}
+
+		score := (1 - float64(abs(dt))/float64(matchAfter) + overlap) / 2
+		if score < minMatchScore {
+			continue
+		}
+		if best == nil || score >= best.Score {
+			best = &model.UserquakeMatch{EarthquakeID: eq.ObjectID, Title: eq.Title(), Score: score}
+		}
+	}
+	return best
+}
+
+// 信頼度 A・B の地域の都道府県
+func userquakePrefs(uq *model.Userquake) map[string]bool {
+	prefs := make(map[string]bool)
+	for _, area := range uq.Areas {
+		if area.Label != "A" && area.Label != "B" {
+			continue
+		}
+		if pref := model.AreaPref(area.Code); pref != "" {
+			prefs[pref] = true
+		}
+	}
+	return prefs
+}
+
+// prefs のうち震度が観測された都道府県の割合
+func prefOverlap(prefs map[string]bool, eq *model.Earthquake) float64 {
+	observed := make(map[string]bool)
+	for _, p := range eq.Points {
+		observed[model.NormalizePref(p.Pref)] = true
+	}
+
+	n := 0
+	for pref := range prefs {
+		if observed[pref] {
+			n++
+		}
+	}
+	return float64(n) / float64(len(prefs))
+}
@@ -0,0 +1,150 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type countingRepository struct {
+	repository.Repository
+	finds int
+}
+
+func (c *countingRepository) FindJmas(ctx context.Context, page repository.Page) ([]bson.M, error) {
+	c.finds++
+	return c.Repository.FindJmas(ctx, page)
+}
+
+func detailScale(n byte, origin time.Time, pref string) bson.M {
+	t := origin.In(model.JST)
+	return bson.M{
+		"_id": oid(n), "code": 551, "time": t.Add(5 * time.Minute).Format("2006/01/02 15:04:05.000"),
+		"issue":      bson.M{"type": "DetailScale", "time": t.Add(5 * time.Minute).Format("2006/01/02 15:04:05")},
+		"earthquake": bson.M{"time": t.Format("2006/01/02 15:04:05"), "maxScale": 30, "hypocenter": bson.M{"name": "石川県能登地方", "latitude": 37.5, "longitude": 137.2, "depth": 10, "magnitude": 4.5}},
+		"points":     bson.A{bson.M{"pref": pref, "addr": "輪島市", "isArea": false, "scale": 30}},
+	}
+}
+
+func userquakeAt(n byte, started time.Time, area string) bson.M {
+	t := started.In(model.JST)
+	return bson.M{
+		"_id": oid(n), "code": 9611, "confidence": 0.95,
+		"time":             t.Add(20 * time.Second).Format("2006/01/02 15:04:05.000"),
+		"started_at":       t.Format("2006/01/02 15:04:05.000"),
+		"updated_at":       t.Add(20 * time.Second).Format("2006/01/02 15:04:05.000"),
+		"area_confidences": bson.M{area: bson.M{"confidence": 0.9}},
+	}
+}
+
+// 保存された結果
+func savedMatch(t *testing.T, repo repository.Repository, n byte) *model.UserquakeMatchRecord {
+	t.Helper()
+	item, err := repo.FindByID(context.Background(), oid(n).Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var uq model.UserquakeRecord
+	bytes, _ := bson.Marshal(item)
+	bson.Unmarshal(bytes, &uq)
+	return uq.Match
+}
+
+func TestAnnotateSettled(t *testing.T) {
+	origin := time.Date(2024, 1, 1, 16, 10, 0, 0, model.JST)
+	repo := &countingRepository{Repository: repository.NewMemory(
+		detailScale(1, origin, "石川県"),
+		// 石川能登で揺れを感じた
+		userquakeAt(2, origin.Add(10*time.Second), "320"),
+		// 北海道 石狩。震度の観測された都道府県と重ならない
+		userquakeAt(3, origin.Add(20*time.Second), "10"),
+	)}
+	c := NewCorrelator(repo)
+
+	items, err := repo.FindUserquakes(context.Background(), repository.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Annotate(context.Background(), items)
+	if repo.finds != 1 {
+		t.Errorf("FindJmas called %d times, want 1", repo.finds)
+	}
+
+	for _, item := range items {
+		uq, _ := model.ToUserquake(item)
+		switch uq.ObjectID {
+		case oid(2).Hex():
+			if uq.Match == nil || uq.Match.EarthquakeID != oid(1).Hex() || uq.Match.Score < minMatchScore {
+				t.Errorf("match of 2 = %+v, want earthquake 1", uq.Match)
+			}
+		case oid(3).Hex():
+			if !uq.Checked || uq.Match != nil {
+				t.Errorf("match of 3 = %+v, checked %v, want none", uq.Match, uq.Checked)
+			}
+		}
+	}
+
+	// 結果は地震感知情報に保存される
+	if m := savedMatch(t, repo, 2); m == nil || !m.Found || !m.Settled || m.Match.EarthquakeID != oid(1).Hex() {
+		t.Errorf("saved match of 2 = %+v", m)
+	}
+	if m := savedMatch(t, repo, 3); m == nil || m.Found || !m.Settled {
+		t.Errorf("saved match of 3 = %+v", m)
+	}
+
+	// 落ち着いた結果は読み直しても調べ直さない
+	items, err = repo.FindUserquakes(context.Background(), repository.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Annotate(context.Background(), items)
+	if repo.finds != 1 {
+		t.Errorf("FindJmas called %d times after settled, want 1", repo.finds)
+	}
+}
+
+func TestAnnotateUnsettled(t *testing.T) {
+	origin := time.Now().Add(-10 * time.Minute)
+	memory := repository.NewMemory(userquakeAt(2, origin.Add(10*time.Second), "320"))
+	repo := &countingRepository{Repository: memory}
+	c := NewCorrelator(repo)
+
+	annotate := func() *model.Userquake {
+		items, err := repo.FindUserquakes(context.Background(), repository.Page{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Annotate(context.Background(), items)
+		uq, _ := model.ToUserquake(items[0])
+		return uq
+	}
+
+	if uq := annotate(); !uq.Checked || uq.Match != nil {
+		t.Errorf("match = %+v, want none", uq.Match)
+	}
+	if m := savedMatch(t, repo, 2); m == nil || m.Settled {
+		t.Errorf("saved match = %+v, want unsettled", m)
+	}
+
+	// 後から届いた地震情報で調べ直す
+	memory.Insert(detailScale(1, origin, "石川県"))
+	if uq := annotate(); uq.Match == nil || uq.Match.EarthquakeID != oid(1).Hex() {
+		t.Errorf("match = %+v, want earthquake 1", uq.Match)
+	}
+	if repo.finds != 2 {
+		t.Errorf("FindJmas called %d times, want 2", repo.finds)
+	}
+}
+
+func TestAnnotateNil(t *testing.T) {
+	item := userquakeAt(2, time.Now(), "320")
+	var c *Correlator
+	c.Annotate(context.Background(), []bson.M{item})
+	if _, ok := item["match"]; ok {
+		t.Error("nil Correlator wrote a match")
+	}
+}
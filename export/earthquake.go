@@ -0,0 +1,128 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Excel で文字化けしないようにするための BOM
+const bom = "\ufeff"
+
+var earthquakeHeader = []string{
+	"origin_time",
+	"issue_time",
+	"issue_type",
+	"hypocenter",
+	"latitude",
+	"longitude",
+	"depth",
+	"magnitude",
+	"max_scale",
+	"max_scale_code",
+	"domestic_tsunami",
+	"foreign_tsunami",
+	"id",
+}
+
+// 地震情報（551）を 1 件 1 行の CSV で書き出す。flush は一定件数ごとに呼ばれる
+func WriteEarthquakes(ctx context.Context, repo repository.Repository, filter repository.EarthquakeFilter, page repository.Page, w io.Writer, withBOM bool, flush func()) error {
+	if withBOM {
+		if _, err := io.WriteString(w, bom); err != nil {
+			return err
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(earthquakeHeader); err != nil {
+		return err
+	}
+
+	n := 0
+	err := repo.EachEarthquake(ctx, filter, page, func(item bson.M) error {
+		if err := cw.Write(earthquakeRow(item)); err != nil {
+			return err
+		}
+
+		n++
+		if n%1000 == 0 {
+			cw.Flush()
+			if flush != nil {
+				flush()
+			}
+		}
+		return cw.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func earthquakeRow(item bson.M) []string {
+	var eq model.EarthquakeRecord
+	bytes, _ := bson.Marshal(item)
+	bson.Unmarshal(bytes, &eq)
+
+	h := eq.Earthquake.Hypocenter
+	return []string{
+		isoTime(eq.Earthquake.Time),
+		isoTime(eq.Issue.Time),
+		eq.Issue.Type,
+		h.Name,
+		coordinate(h.Latitude, 90),
+		coordinate(h.Longitude, 180),
+		depth(h.Depth),
+		magnitude(h.Magnitude),
+		maxScale(eq.Earthquake.MaxScale),
+		strconv.Itoa(eq.Earthquake.MaxScale),
+		eq.Earthquake.DomesticTsunami,
+		eq.Earthquake.ForeignTsunami,
+		eq.ID.Hex(),
+	}
+}
+
+// 不明な値（-1、緯度・経度は -200）は空欄にする
+func coordinate(v, limit float64) string {
+	if v < -limit || v > limit {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func depth(km int) string {
+	if km < 0 {
+		return ""
+	}
+	return strconv.Itoa(km)
+}
+
+func magnitude(m float64) string {
+	if m < 0 {
+		return ""
+	}
+	return strconv.FormatFloat(m, 'f', -1, 64)
+}
+
+func maxScale(code int) string {
+	if code <= 0 {
+		return ""
+	}
+	return model.ScaleName(code)
+}
+
+// 解釈できない場合はそのまま出力する
+func isoTime(t string) string {
+	if p := model.ParseTime(t); p != nil {
+		return p.Format(time.RFC3339)
+	}
+	return t
+}
@@ -0,0 +1,92 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func oid(n byte) primitive.ObjectID {
+	var id primitive.ObjectID
+	id[len(id)-1] = n
+	return id
+}
+
+func earthquakeItem(n byte, time string, h bson.M, maxScale int) bson.M {
+	return bson.M{
+		"_id": oid(n), "code": 551, "time": time,
+		"issue": bson.M{"type": "DetailScale", "time": time[:19]},
+		"earthquake": bson.M{
+			"time": "2026/10/01 09:58:00", "maxScale": maxScale, "hypocenter": h,
+			"domesticTsunami": "None", "foreignTsunami": "Unknown",
+		},
+	}
+}
+
+func TestEarthquakeRow(t *testing.T) {
+	tests := []struct {
+		name string
+		item bson.M
+		want []string
+	}{
+		{
+			name: "known",
+			item: earthquakeItem(1, "2026/10/01 10:00:00.000", bson.M{"name": "石川県能登地方", "latitude": 37.5, "longitude": 137.2, "depth": 10, "magnitude": 5.6}, 50),
+			want: []string{"2026-10-01T09:58:00+09:00", "2026-10-01T10:00:00+09:00", "DetailScale", "石川県能登地方", "37.5", "137.2", "10", "5.6", "5強", "50", "None", "Unknown", oid(1).Hex()},
+		},
+		{
+			// ごく浅い（0km）は不明ではない
+			name: "very shallow",
+			item: earthquakeItem(2, "2026/10/01 10:00:00.000", bson.M{"name": "熊本県熊本地方", "latitude": 32.7, "longitude": 130.8, "depth": 0, "magnitude": 3.0}, 10),
+			want: []string{"2026-10-01T09:58:00+09:00", "2026-10-01T10:00:00+09:00", "DetailScale", "熊本県熊本地方", "32.7", "130.8", "0", "3", "1", "10", "None", "Unknown", oid(2).Hex()},
+		},
+		{
+			name: "unknown",
+			item: earthquakeItem(3, "2026/10/01 10:00:00.000", bson.M{"name": "", "latitude": -200, "longitude": -200, "depth": -1, "magnitude": -1}, -1),
+			want: []string{"2026-10-01T09:58:00+09:00", "2026-10-01T10:00:00+09:00", "DetailScale", "", "", "", "", "", "", "-1", "None", "Unknown", oid(3).Hex()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := earthquakeRow(tt.item); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("earthquakeRow() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteEarthquakes(t *testing.T) {
+	repo := repository.NewMemory(
+		earthquakeItem(1, "2026/10/01 10:00:00.000", bson.M{"name": "石川県能登地方", "latitude": 37.5, "longitude": 137.2, "depth": 10, "magnitude": 5.6}, 50),
+		earthquakeItem(2, "2026/10/02 10:00:00.000", bson.M{"name": "熊本県熊本地方", "latitude": 32.7, "longitude": 130.8, "depth": 0, "magnitude": 3.0}, 10),
+		bson.M{"_id": oid(3), "code": 552, "time": "2026/10/02 11:00:00.000"},
+	)
+
+	var b bytes.Buffer
+	flushes := 0
+	err := WriteEarthquakes(context.Background(), repo, repository.EarthquakeFilter{MinScale: 30}, repository.Page{}, &b, true, func() { flushes++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines = %q, want a header and 1 row", lines)
+	}
+	if want := bom + strings.Join(earthquakeHeader, ","); lines[0] != want {
+		t.Errorf("header = %q, want %q", lines[0], want)
+	}
+	if !strings.HasSuffix(lines[1], oid(1).Hex()) {
+		t.Errorf("row = %q", lines[1])
+	}
+	if flushes != 0 {
+		t.Errorf("flushed %d times for 1 row", flushes)
+	}
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	apiDefaultLimit = 50
+	apiMaxLimit     = 200
+)
+
+type apiEvent struct {
+	ID   string      `json:"id"`
+	Code int         `json:"code"`
+	Time *time.Time  `json:"time,omitempty"`
+	Data interface{} `json:"data"`
+}
+
+type apiEvents struct {
+	Items  []apiEvent `json:"items"`
+	Since  string     `json:"since"`            // 次回のポーリングで since に渡す
+	Before string     `json:"before,omitempty"` // さらに古いものを取得するときに before に渡す
+	More   bool       `json:"more"`             // since 指定時、取得しきれなかった新しいものがある
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func (s *Service) EventsHandler(w http.ResponseWriter, r *http.Request) {
+	page, err := parseEventsQuery(r.URL.Query())
+	if err != nil {
+		ResponseJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, more, err := s.findIndexItems(r.Context(), page)
+	if err != nil {
+		log.Printf("Find error: %v\n", err)
+		ResponseJSONError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	result := apiEvents{Items: []apiEvent{}}
+	for _, item := range items {
+		e, err := toAPIEvent(item)
+		if err != nil {
+			log.Printf("Convert error: %v\n", err)
+			continue
+		}
+		result.Items = append(result.Items, e)
+	}
+
+	// 次回のポーリング位置
+	if len(items) > 0 {
+		result.Since = repository.CursorOf(items[0]).String()
+	} else if page.After != nil {
+		result.Since = page.After.String()
+	} else {
+		result.Since = repository.Cursor{Time: time.Now().In(model.JST).Format("2006/01/02 15:04:05")}.String()
+	}
+	if page.After != nil {
+		result.More = more
+	} else if more {
+		result.Before = repository.CursorOf(items[len(items)-1]).String()
+	}
+
+	ResponseJSON(w, http.StatusOK, result)
+}
+
+func toAPIEvent(item bson.M) (apiEvent, error) {
+	data, err := model.Convert(item)
+	if err != nil {
+		return apiEvent{}, err
+	}
+
+	c := repository.CursorOf(item)
+	return apiEvent{
+		ID:   c.ID.Hex(),
+		Code: model.ToInt(item["code"]),
+		Time: model.ParseTime(c.Time),
+		Data: data,
+	}, nil
+}
+
+// code, from, to, since, before, limit を読み取る
+func parseEventsQuery(q url.Values) (repository.Page, error) {
+	page := repository.Page{Limit: apiDefaultLimit}
+
+	for _, v := range q["code"] {
+		for _, c := range strings.Split(v, ",") {
+			code, err := strconv.Atoi(strings.TrimSpace(c))
+			if err != nil {
+				return page, errors.New("invalid code")
+			}
+			page.Codes = append(page.Codes, code)
+		}
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return page, errors.New("invalid limit")
+		}
+		page.Limit = min(limit, apiMaxLimit)
+	}
+
+	var err error
+	if page.Since, err = timeParam(q, "from", false); err != nil {
+		return page, err
+	}
+	if page.Until, err = timeParam(q, "to", true); err != nil {
+		return page, err
+	}
+
+	if v := q.Get("since"); v != "" {
+		if page.After, err = repository.ParseCursor(v); err != nil {
+			return page, errors.New("invalid since")
+		}
+	}
+	if v := q.Get("before"); v != "" {
+		if page.Before, err = repository.ParseCursor(v); err != nil {
+			return page, errors.New("invalid before")
+		}
+	}
+	if page.After != nil && page.Before != nil {
+		return page, errors.New("since and before cannot be used together")
+	}
+
+	return page, nil
+}
+
+// 日付（2006-01-02）または RFC 3339 の日時を保存形式の文字列にする。
+// endOfDay なら日付指定はその日の終わりまでを含める
+func timeParam(q url.Values, key string, endOfDay bool) (string, error) {
+	v := q.Get(key)
+	if v == "" {
+		return "", nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", v, model.JST); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t.Format("2006/01/02 15:04:05"), nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.In(model.JST).Format("2006/01/02 15:04:05"), nil
+	}
+	return "", errors.New("invalid " + key)
+}
+
+func ResponseJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func ResponseJSONError(w http.ResponseWriter, code int, message string) {
+	ResponseJSON(w, code, apiError{Error: message})
+}
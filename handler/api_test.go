@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseEventsQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  repository.Page
+		err   string
+	}{
+		{query: "", want: repository.Page{Limit: apiDefaultLimit}},
+		{query: "code=551,552&code=9611", want: repository.Page{Limit: apiDefaultLimit, Codes: []int{551, 552, 9611}}},
+		{query: "limit=10", want: repository.Page{Limit: 10}},
+		{query: "limit=1000", want: repository.Page{Limit: apiMaxLimit}},
+		{query: "from=2026-10-01&to=2026-10-02", want: repository.Page{Limit: apiDefaultLimit, Since: "2026/10/01 00:00:00", Until: "2026/10/03 00:00:00"}},
+		// RFC 3339 はサーバのタイムゾーンによらず日本時間にする
+		{query: "from=2026-10-01T00:00:00Z&to=2026-10-01T12:30:00%2B09:00", want: repository.Page{Limit: apiDefaultLimit, Since: "2026/10/01 09:00:00", Until: "2026/10/01 12:30:00"}},
+		{query: "code=x", err: "invalid code"},
+		{query: "limit=0", err: "invalid limit"},
+		{query: "from=2026/10/01", err: "invalid from"},
+		{query: "since=!!", err: "invalid since"},
+		{query: "since=" + repository.Cursor{Time: "2026/10/01 00:00:00"}.String() + "&before=" + repository.Cursor{Time: "2026/10/01 00:00:00"}.String(), err: "since and before cannot be used together"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			page, err := parseEventsQuery(q)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("parseEventsQuery() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(page, tt.want) {
+				t.Errorf("parseEventsQuery() = %+v, want %+v", page, tt.want)
+			}
+		})
+	}
+}
+
+func oid(n byte) primitive.ObjectID {
+	var id primitive.ObjectID
+	id[len(id)-1] = n
+	return id
+}
+
+func apiFixtures() *repository.Memory {
+	return repository.NewMemory(
+		bson.M{"_id": oid(1), "code": 551, "time": "2026/10/01 10:00:00.000", "issue": bson.M{"type": "DetailScale", "time": "2026/10/01 10:00:00"}, "earthquake": bson.M{"time": "2026/10/01 09:58:00", "maxScale": 30, "hypocenter": bson.M{"name": "石川県能登地方", "latitude": 37.5, "longitude": 137.2, "depth": 10, "magnitude": 5.6}}},
+		bson.M{"_id": oid(2), "code": 552, "time": "2026/10/01 10:05:00.000", "issue": bson.M{"time": "2026/10/01 10:05:00", "type": "Focus"}, "cancelled": true},
+		bson.M{"_id": oid(3), "code": 9611, "confidence": 0.95, "started_at": "2026/10/01 10:10:00.000", "updated_at": "2026/10/01 10:10:20.000", "time": "2026/10/01 10:10:20.000"},
+		bson.M{"_id": oid(4), "code": 551, "time": "2026/10/02 10:00:00.000", "issue": bson.M{"type": "ScalePrompt", "time": "2026/10/02 10:00:00"}, "earthquake": bson.M{"time": "2026/10/02 09:58:00", "maxScale": 10}},
+	)
+}
+
+func getEvents(t *testing.T, s *Service, query string) (int, apiEvents) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	s.EventsHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/events?"+query, nil))
+
+	var result apiEvents
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return w.Code, result
+}
+
+func eventIDs(result apiEvents) []string {
+	var ids []string
+	for _, e := range result.Items {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestEventsHandler(t *testing.T) {
+	s := &Service{Repository: apiFixtures()}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []byte
+		more  bool
+	}{
+		{name: "all", want: []byte{4, 3, 2, 1}},
+		{name: "code", query: "code=551", want: []byte{4, 1}},
+		{name: "userquake only", query: "code=9611", want: []byte{3}},
+		{name: "period", query: "from=2026-10-01&to=2026-10-01", want: []byte{3, 2, 1}},
+		{name: "limit", query: "limit=2", want: []byte{4, 3}},
+		{name: "since", query: "since=" + repository.CursorOf(bson.M{"_id": oid(1), "time": "2026/10/01 10:00:00.000"}).String() + "&limit=2", want: []byte{3, 2}, more: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, result := getEvents(t, s, tt.query)
+			if code != http.StatusOK {
+				t.Fatalf("status = %d", code)
+			}
+			var want []string
+			for _, n := range tt.want {
+				want = append(want, oid(n).Hex())
+			}
+			if got := eventIDs(result); !reflect.DeepEqual(got, want) {
+				t.Errorf("items = %v, want %v", got, want)
+			}
+			if result.More != tt.more {
+				t.Errorf("more = %v, want %v", result.More, tt.more)
+			}
+		})
+	}
+
+	if code, _ := getEvents(t, s, "limit=x"); code != http.StatusBadRequest {
+		t.Errorf("status with an invalid limit = %d, want 400", code)
+	}
+}
+
+// 次回の since で、取得済みのものより新しいものだけを取得できる
+func TestEventsHandlerPolling(t *testing.T) {
+	memory := apiFixtures()
+	s := &Service{Repository: memory}
+
+	_, first := getEvents(t, s, "limit=2")
+	if first.Before == "" {
+		t.Fatal("before is empty while more items exist")
+	}
+	_, older := getEvents(t, s, "limit=2&before="+url.QueryEscape(first.Before))
+	if got, want := eventIDs(older), []string{oid(2).Hex(), oid(1).Hex()}; !reflect.DeepEqual(got, want) {
+		t.Errorf("older items = %v, want %v", got, want)
+	}
+
+	memory.Insert(bson.M{"_id": oid(5), "code": 552, "time": "2026/10/02 11:00:00.000", "issue": bson.M{"time": "2026/10/02 11:00:00", "type": "Focus"}, "cancelled": true})
+	_, next := getEvents(t, s, "since="+url.QueryEscape(first.Since))
+	if got, want := eventIDs(next), []string{oid(5).Hex()}; !reflect.DeepEqual(got, want) {
+		t.Errorf("polled items = %v, want %v", got, want)
+	}
+
+	_, empty := getEvents(t, s, "since="+url.QueryEscape(next.Since))
+	if len(empty.Items) != 0 || empty.Since != next.Since {
+		t.Errorf("polling without new items = %+v, want no items and the same since", empty)
+	}
+}
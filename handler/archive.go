@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (s *Service) ArchiveYearHandler(w http.ResponseWriter, r *http.Request) {
+	year, ok := archiveDate(r.PathValue("year"), "1", "1")
+	if !ok {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	counts, err := s.countArchive(r, year, year.AddDate(1, 0, 0), "2006/01")
+	if err != nil {
+		log.Printf("Count error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 月ごとに集計。/archive/{year}
+	root := renderer.Root(1)
+	summary := renderer.ArchiveSummary{
+		Title: year.Format("2006年"),
+		Prev:  archiveLink(root, year.AddDate(-1, 0, 0), "2006"),
+		Next:  archiveLink(root, year.AddDate(1, 0, 0), "2006"),
+		Rows:  summarize(counts, year, 12, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }, "01月", root, "2006/01"),
+	}
+
+	s.renderArchive(w, "archive_summary.html", root, summary)
+}
+
+func (s *Service) ArchiveMonthHandler(w http.ResponseWriter, r *http.Request) {
+	month, ok := archiveDate(r.PathValue("year"), r.PathValue("month"), "1")
+	if !ok {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	next := month.AddDate(0, 1, 0)
+	counts, err := s.countArchive(r, month, next, "2006/01/02")
+	if err != nil {
+		log.Printf("Count error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 日ごとに集計。/archive/{year}/{month}
+	root := renderer.Root(2)
+	days := next.AddDate(0, 0, -1).Day()
+	summary := renderer.ArchiveSummary{
+		Title: month.Format("2006年01月"),
+		Up:    archiveLink(root, month, "2006"),
+		Prev:  archiveLink(root, month.AddDate(0, -1, 0), "2006/01"),
+		Next:  archiveLink(root, next, "2006/01"),
+		Rows:  summarize(counts, month, days, func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }, "01月02日", root, "2006/01/02"),
+	}
+
+	s.renderArchive(w, "archive_summary.html", root, summary)
+}
+
+func (s *Service) ArchiveDayHandler(w http.ResponseWriter, r *http.Request) {
+	day, ok := archiveDate(r.PathValue("year"), r.PathValue("month"), r.PathValue("day"))
+	if !ok {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	items, err := s.findArchiveItems(r, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		log.Printf("Find error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.Correlator.Annotate(r.Context(), items)
+
+	data, err := renderer.ConvertAll(items)
+	if err != nil {
+		log.Printf("Render error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// /archive/{year}/{month}/{day}
+	root := renderer.Root(3)
+	s.renderArchive(w, "archive_day.html", root, renderer.ArchiveDay{
+		Title: day.Format("2006年01月02日"),
+		Up:    archiveLink(root, day, "2006/01"),
+		Prev:  archiveLink(root, day.AddDate(0, 0, -1), "2006/01/02"),
+		Next:  archiveLink(root, day.AddDate(0, 0, 1), "2006/01/02"),
+		Items: data,
+	})
+}
+
+func (s *Service) findArchiveItems(r *http.Request, since, until time.Time) ([]bson.M, error) {
+	items, _, err := s.findIndexItems(r.Context(), repository.Page{
+		Since: since.Format("2006/01/02 15:04:05"),
+		Until: until.Format("2006/01/02 15:04:05"),
+	})
+	return items, err
+}
+
+// 期間内の件数を、受信時刻を layout の長さで区切って数える
+func (s *Service) countArchive(r *http.Request, since, until time.Time, layout string) ([]repository.ArchiveCount, error) {
+	return s.Repository.CountArchive(r.Context(), repository.Page{
+		Since: since.Format("2006/01/02 15:04:05"),
+		Until: until.Format("2006/01/02 15:04:05"),
+	}, len(layout))
+}
+
+func (s *Service) renderArchive(w http.ResponseWriter, templateFile, root string, data interface{}) {
+	html, err := renderer.Render(templateFile, root, data)
+	if err != nil {
+		log.Printf("Render error: %v\n", err)
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	w.Write([]byte(html))
+}
+
+// 期間ごとの件数と最大震度を集計する
+func summarize(counts []repository.ArchiveCount, start time.Time, n int, step func(time.Time) time.Time, label, root, link string) []renderer.ArchiveRow {
+	rows := make([]renderer.ArchiveRow, n)
+	keys := make(map[string]int, n)
+	for i, t := 0, start; i < n; i, t = i+1, step(t) {
+		rows[i] = renderer.ArchiveRow{Label: t.Format(label), Link: archiveLink(root, t, link)}
+		keys[t.Format(link)] = i
+	}
+
+	for _, c := range counts {
+		i, ok := keys[c.Key]
+		if !ok {
+			continue
+		}
+
+		row := &rows[i]
+		row.Total += c.Count
+		switch c.Code {
+		case 551:
+			row.Earthquakes += c.Count
+			if c.MaxScale > row.MaxScaleCode {
+				row.MaxScaleCode = c.MaxScale
+				row.MaxScale = model.ScaleName(c.MaxScale)
+			}
+		case 552:
+			row.Tsunamis += c.Count
+		case 556:
+			row.EEWs += c.Count
+		case 9611:
+			row.Userquakes += c.Count
+		}
+	}
+
+	return rows
+}
+
+func archiveDate(year, month, day string) (time.Time, bool) {
+	y, err1 := strconv.Atoi(year)
+	m, err2 := strconv.Atoi(month)
+	d, err3 := strconv.Atoi(day)
+	if err1 != nil || err2 != nil || err3 != nil || y < 1000 || y > 9999 {
+		return time.Time{}, false
+	}
+
+	t := time.Date(y, time.Month(m), d, 0, 0, 0, 0, model.JST)
+	if t.Month() != time.Month(m) || t.Day() != d {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// root はリンクを置くページからサイトのルートへの相対パス
+func archiveLink(root string, t time.Time, layout string) string {
+	return fmt.Sprintf("%sarchive/%s", root, t.Format(layout))
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/p2pquake/web-client/event"
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// /event/{id}: id の情報と同じ地震の情報をまとめて表示する
+func (s *Service) EventPageHandler(w http.ResponseWriter, r *http.Request) {
+	item, err := s.Repository.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if !errors.Is(err, repository.ErrInvalidID) && !errors.Is(err, repository.ErrNotFound) {
+			log.Printf("Find error: %v\n", err)
+		}
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	e, err := s.findEvent(r.Context(), item)
+	if err != nil {
+		log.Printf("Event error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	html, err := renderer.RenderEvent(e)
+	if err != nil {
+		log.Printf("Render error: %v\n", err)
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	w.Write([]byte(html))
+}
+
+// 同じ地震の情報をまとめたものを覚えておく時間。新しい情報が届いても長くは古いままにならないよう短くする
+const EventCacheTTL = 30 * time.Second
+
+// item の受信時刻の前後から同じ地震の情報を探す
+func (s *Service) findEvent(ctx context.Context, item bson.M) (*event.Event, error) {
+	t := model.ParseTime(event.ItemTime(item))
+	if t == nil {
+		return event.Group(item, nil)
+	}
+
+	key := repository.CursorOf(item).ID.Hex()
+	if e, ok := s.Events.Get(key); ok {
+		return e, nil
+	}
+
+	page := repository.Page{
+		Since: t.Add(-event.SearchWindow).Format("2006/01/02 15:04:05"),
+		Until: t.Add(event.SearchWindow).Format("2006/01/02 15:04:05"),
+	}
+	jmaItems, err := s.Repository.FindJmas(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	userquakeItems, err := s.Repository.FindUserquakes(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	s.Correlator.Annotate(ctx, userquakeItems)
+
+	e, err := event.Group(item, append(jmaItems, userquakeItems...))
+	if err != nil {
+		return nil, err
+	}
+	s.Events.Set(key, e)
+	return e, nil
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/p2pquake/web-client/export"
+	"github.com/p2pquake/web-client/repository"
+)
+
+// 検索と同じパラメータで地震情報を CSV として返す。bom=1 で BOM を付ける
+func (s *Service) ExportEarthquakesHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, page, err := repository.ParseEarthquakeQuery(q)
+	if err != nil {
+		ResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=UTF-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="earthquakes.csv"`)
+
+	flush := func() {
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+
+	// 書き出し始めた後はステータスを変えられないのでログのみ
+	if err := export.WriteEarthquakes(r.Context(), s.Repository, filter, page, w, q.Get("bom") == "1", flush); err != nil {
+		log.Printf("Export error: %v\n", err)
+	}
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// フィードに載せる件数
+const feedSize = 50
+
+// フィードの絞り込み条件
+type feedFilter struct {
+	minScale int
+	prefs    []string
+}
+
+func (s *Service) AtomHandler(w http.ResponseWriter, r *http.Request) {
+	s.feedHandler(w, r, "application/atom+xml", renderer.RenderAtom)
+}
+
+func (s *Service) RSSHandler(w http.ResponseWriter, r *http.Request) {
+	s.feedHandler(w, r, "application/rss+xml", renderer.RenderRSS)
+}
+
+func (s *Service) feedHandler(w http.ResponseWriter, r *http.Request, contentType string, render func(renderer.Feed) ([]byte, error)) {
+	q := r.URL.Query()
+	page := repository.Page{}
+	for _, v := range q["code"] {
+		for _, c := range strings.Split(v, ",") {
+			code, err := strconv.Atoi(strings.TrimSpace(c))
+			if err != nil {
+				ResponseError(w, http.StatusBadRequest, "invalid code")
+				return
+			}
+			page.Codes = append(page.Codes, code)
+		}
+	}
+
+	filter := feedFilter{}
+	for _, pref := range nonEmpty(q["pref"]) {
+		filter.prefs = append(filter.prefs, model.NormalizePref(pref))
+	}
+	var err error
+	if filter.minScale, err = intParam(q, "min_scale"); err != nil {
+		ResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// リクエストの Host から組み立てると、偽の Host を付けたリクエストでリーダやキャッシュに残るリンクを差し替えられる
+	base, ok := baseURL()
+	if !ok {
+		ResponseError(w, http.StatusNotFound, "Feeds are not available without BASE_URL")
+		return
+	}
+
+	items, err := s.feedItems(r.Context(), page, filter)
+	if err != nil {
+		log.Printf("Find error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	feed := renderer.Feed{
+		Title:   "P2P地震情報",
+		Link:    base + "/",
+		Self:    base + r.URL.RequestURI(),
+		Updated: time.Now(),
+	}
+	for _, item := range items {
+		data, err := model.Convert(item)
+		if err != nil {
+			log.Printf("Convert error: %v\n", err)
+			continue
+		}
+
+		e, err := toAPIEvent(item)
+		if err != nil {
+			continue
+		}
+		entry := renderer.FeedEntry{
+			ID:      base + "/" + e.ID,
+			Link:    base + "/" + e.ID,
+			Title:   feedTitle(data),
+			Summary: feedSummary(data),
+			Updated: time.Now(),
+		}
+		if e.Time != nil {
+			entry.Updated = *e.Time
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	if len(feed.Entries) > 0 {
+		feed.Updated = feed.Entries[0].Updated
+	}
+
+	b, err := render(feed)
+	if err != nil {
+		log.Printf("Render error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType+"; charset=UTF-8")
+	w.Write(b)
+}
+
+// 地震情報は条件を検索に含めて feedSize 件だけ読み込む。
+// 津波予報・緊急地震速報（警報）は件数が少ないので、新しいものから feedSize 件を読み込んで都道府県で絞り込む
+func (s *Service) feedItems(ctx context.Context, page repository.Page, filter feedFilter) ([]bson.M, error) {
+	var items []bson.M
+	if wantCode(page, 551) {
+		earthquakes, err := s.Repository.SearchEarthquakes(ctx, repository.EarthquakeFilter{
+			MinScale: filter.minScale,
+			Prefs:    filter.prefs,
+		}, repository.Page{Limit: feedSize})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, earthquakes...)
+	}
+
+	// 震度の条件があれば地震情報のみ
+	others := repository.Page{Limit: feedSize}
+	for _, code := range []int{552, 556} {
+		if wantCode(page, code) {
+			others.Codes = append(others.Codes, code)
+		}
+	}
+	if filter.minScale == 0 && len(others.Codes) > 0 {
+		found, err := s.Repository.FindJmas(ctx, others)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range found {
+			data, err := model.Convert(item)
+			if err == nil && filter.matchAreas(data) {
+				items = append(items, item)
+			}
+		}
+	}
+
+	repository.SortNewest(items)
+	if len(items) > feedSize {
+		items = items[:feedSize]
+	}
+	return items, nil
+}
+
+func wantCode(page repository.Page, code int) bool {
+	if len(page.Codes) == 0 {
+		return true
+	}
+	for _, c := range page.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// 津波予報・緊急地震速報（警報）の対象地域が条件の都道府県を含むか。震度の条件は feedItems で扱う
+func (f feedFilter) matchAreas(data interface{}) bool {
+	switch v := data.(type) {
+	case *model.Tsunami:
+		var prefs []string
+		for _, g := range v.AreaByGrade {
+			for _, a := range g.Areas {
+				prefs = append(prefs, model.TsunamiAreaPrefs(a.Name)...)
+			}
+		}
+		return f.matchPrefs(prefs)
+	case *model.EEW:
+		var prefs []string
+		for _, a := range v.Areas {
+			prefs = append(prefs, model.NormalizePref(a))
+		}
+		return f.matchPrefs(prefs)
+	}
+	return false
+}
+
+// 都道府県名が完全に一致するものがあるか（prefs は「石川県」のような正式な名前）
+func (f feedFilter) matchPrefs(prefs []string) bool {
+	if len(f.prefs) == 0 {
+		return true
+	}
+	for _, pref := range f.prefs {
+		for _, p := range prefs {
+			if p == pref {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func feedTitle(data interface{}) string {
+	if t, ok := data.(interface{ Title() string }); ok {
+		return t.Title()
+	}
+	return "不明な情報"
+}
+
+func feedSummary(data interface{}) string {
+	switch v := data.(type) {
+	case *model.Earthquake:
+		return fmt.Sprintf("%s 震源: %s 最大震度: %s %s", v.OccurredTime, v.Hypocenter, v.MaxScale, v.Tsunami)
+	case *model.Tsunami:
+		var areas []string
+		for _, g := range v.AreaByGrade {
+			for _, a := range g.Areas {
+				areas = append(areas, a.Name)
+			}
+		}
+		return fmt.Sprintf("%s発表 %s", v.IssueTime, strings.Join(areas, "、"))
+	case *model.EEW:
+		return fmt.Sprintf("%s発表 震源: %s 強い揺れが予想される地域: %s", v.IssueTime, v.Hypocenter, strings.Join(v.Areas, "、"))
+	}
+	return ""
+}
+
+// 絶対 URL の基準（BASE_URL）。指定がなければ ok は false
+func baseURL() (base string, ok bool) {
+	base = strings.TrimRight(os.Getenv("BASE_URL"), "/")
+	return base, base != ""
+}
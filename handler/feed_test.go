@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFeedFilterMatchAreas(t *testing.T) {
+	tsunami := func(areas ...string) *model.Tsunami {
+		g := model.AreaByGrade{Grade: "Watch"}
+		for _, a := range areas {
+			g.Areas = append(g.Areas, model.ForecastArea{Name: a, Grade: "Watch"})
+		}
+		return &model.Tsunami{AreaByGrade: []model.AreaByGrade{g}}
+	}
+
+	tests := []struct {
+		name   string
+		filter feedFilter
+		data   interface{}
+		want   bool
+	}{
+		{name: "no filter", data: tsunami("石川県能登"), want: true},
+		{name: "tsunami area", filter: feedFilter{prefs: []string{"石川県"}}, data: tsunami("石川県能登"), want: true},
+		{name: "tsunami bay", filter: feedFilter{prefs: []string{"東京都"}}, data: tsunami("東京湾内湾"), want: true},
+		{name: "tsunami islands", filter: feedFilter{prefs: []string{"東京都"}}, data: tsunami("伊豆諸島"), want: true},
+		{name: "tsunami other area", filter: feedFilter{prefs: []string{"京都府"}}, data: tsunami("東京湾内湾")},
+		{name: "eew short name", filter: feedFilter{prefs: []string{"京都府"}}, data: &model.EEW{Areas: []string{"京都", "大阪"}}, want: true},
+		{name: "eew other", filter: feedFilter{prefs: []string{"京都府"}}, data: &model.EEW{Areas: []string{"東京"}}},
+		{name: "other information", data: &model.Userquake{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matchAreas(tt.data); got != tt.want {
+				t.Errorf("matchAreas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func feedFixtures() *repository.Memory {
+	earthquake := func(n byte, time string, maxScale int, pref string) bson.M {
+		return bson.M{
+			"_id": oid(n), "code": 551, "time": time,
+			"issue":      bson.M{"type": "DetailScale", "time": time[:19]},
+			"earthquake": bson.M{"time": time[:19], "maxScale": maxScale, "hypocenter": bson.M{"name": pref}},
+			"points":     bson.A{bson.M{"pref": pref, "addr": pref, "isArea": false, "scale": maxScale}},
+		}
+	}
+	return repository.NewMemory(
+		earthquake(1, "2026/10/01 10:00:00.000", 30, "京都府"),
+		earthquake(2, "2026/10/01 11:00:00.000", 50, "東京都"),
+		bson.M{"_id": oid(3), "code": 552, "time": "2026/10/01 12:00:00.000", "issue": bson.M{"time": "2026/10/01 12:00:00", "type": "Focus"},
+			"areas": bson.A{bson.M{"name": "東京湾内湾", "grade": "Watch"}}},
+		bson.M{"_id": oid(4), "code": 556, "time": "2026/10/01 13:00:00.000", "issue": bson.M{"time": "2026/10/01 13:00:00", "serial": "1"},
+			"areas": bson.A{bson.M{"pref": "京都", "name": "京都府南部"}}},
+		bson.M{"_id": oid(5), "code": 9611, "confidence": 0.95, "started_at": "2026/10/01 14:00:00.000", "time": "2026/10/01 14:00:00.000"},
+	)
+}
+
+func TestFeedItems(t *testing.T) {
+	s := &Service{Repository: feedFixtures()}
+
+	tests := []struct {
+		name   string
+		codes  []int
+		filter feedFilter
+		want   []byte
+	}{
+		{name: "all", want: []byte{4, 3, 2, 1}},
+		{name: "codes", codes: []int{551, 556}, want: []byte{4, 2, 1}},
+		{name: "京都府", filter: feedFilter{prefs: []string{"京都府"}}, want: []byte{4, 1}},
+		{name: "東京都", filter: feedFilter{prefs: []string{"東京都"}}, want: []byte{3, 2}},
+		// 震度の条件があれば地震情報のみ
+		{name: "min scale", filter: feedFilter{minScale: 45}, want: []byte{2}},
+		{name: "min scale and pref", filter: feedFilter{minScale: 10, prefs: []string{"京都府"}}, want: []byte{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := s.feedItems(context.Background(), repository.Page{Codes: tt.codes}, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []byte
+			for _, item := range items {
+				id := repository.CursorOf(item).ID
+				got = append(got, id[len(id)-1])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("feedItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedHandler(t *testing.T) {
+	s := &Service{Repository: feedFixtures()}
+	get := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Host = "attacker.example"
+		w := httptest.NewRecorder()
+		s.AtomHandler(w, r)
+		return w
+	}
+
+	// BASE_URL がなければリクエストの Host からリンクを作らない
+	t.Setenv("BASE_URL", "")
+	if w := get("/feed.atom"); w.Code != http.StatusNotFound {
+		t.Errorf("status without BASE_URL = %d, want 404", w.Code)
+	}
+
+	t.Setenv("BASE_URL", "https://example.com/web/")
+	w := get("/feed.atom?pref=京都")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	body := w.Body.String()
+	if strings.Contains(body, "attacker.example") {
+		t.Error("feed contains the request host")
+	}
+	for _, want := range []string{"https://example.com/web/" + oid(1).Hex(), "https://example.com/web/" + oid(4).Hex()} {
+		if !strings.Contains(body, want) {
+			t.Errorf("feed does not contain %s", want)
+		}
+	}
+	if strings.Contains(body, oid(2).Hex()) {
+		t.Error("feed contains an item of another prefecture")
+	}
+
+	if w := get("/feed.atom?min_scale=x"); w.Code != http.StatusBadRequest {
+		t.Errorf("status with an invalid min_scale = %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
+)
+
+func (s *Service) EventsGeoJSONHandler(w http.ResponseWriter, r *http.Request) {
+	page, err := parseEventsQuery(r.URL.Query())
+	if err != nil {
+		ResponseJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, _, err := s.findIndexItems(r.Context(), page)
+	if err != nil {
+		log.Printf("Find error: %v\n", err)
+		ResponseJSONError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	var features []renderer.Feature
+	for _, item := range items {
+		data, err := model.Convert(item)
+		if err != nil {
+			log.Printf("Convert error: %v\n", err)
+			continue
+		}
+		features = append(features, renderer.ToFeatures(data)...)
+	}
+
+	responseGeoJSON(w, features)
+}
+
+func (s *Service) eventGeoJSON(w http.ResponseWriter, r *http.Request, id string) {
+	item, err := s.Repository.FindByID(r.Context(), id)
+	if errors.Is(err, repository.ErrInvalidID) {
+		ResponseJSONError(w, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		ResponseJSONError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Find error: %v\n", err)
+		ResponseJSONError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	data, err := model.Convert(item)
+	if err != nil {
+		log.Printf("Convert error: %v\n", err)
+		ResponseJSONError(w, http.StatusInternalServerError, "Convert error")
+		return
+	}
+
+	responseGeoJSON(w, renderer.ToFeatures(data))
+}
+
+func responseGeoJSON(w http.ResponseWriter, features []renderer.Feature) {
+	b, err := renderer.RenderGeoJSON(features)
+	if err != nil {
+		log.Printf("Render error: %v\n", err)
+		ResponseJSONError(w, http.StatusInternalServerError, "Render error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.Write(b)
+}
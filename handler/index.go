@@ -4,99 +4,171 @@ import (
 	"context"
 	"log"
 	"net/http"
-	"sort"
+	"net/url"
 	"time"
 
+	"github.com/p2pquake/web-client/model"
 	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func (s *Service) IndexHandler(w http.ResponseWriter, r *http.Request) {
-	threeDaysAgo := time.Now().Add(time.Hour * -72).Format("2006/01/02 15:04:05")
+// 過去の情報を 1 ページに表示する件数
+const pageSize = 50
 
-	// 地震情報・津波予報・緊急地震速報（警報）
-	jmaItems, err := s.findJmas(threeDaysAgo)
+func (s *Service) IndexHandler(w http.ResponseWriter, r *http.Request) {
+	page, ok, err := cursorPage(r)
 	if err != nil {
-		log.Printf("Render error: %v\n", err)
-		ResponseError(w, http.StatusInternalServerError, err.Error())
+		ResponseError(w, http.StatusBadRequest, "Invalid cursor")
 		return
 	}
 
-	// 地震感知情報
-	userquakeItems, err := s.findUserquakes(threeDaysAgo)
-	if err != nil {
-		log.Printf("Render error: %v\n", err)
-		ResponseError(w, http.StatusInternalServerError, err.Error())
-		return
+	// 先頭ページは事前に描画したものを返す
+	if !ok && s.Snapshot != nil {
+		if html := s.Snapshot.Load(); html != nil {
+			w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+			w.Write(html)
+			return
+		}
 	}
 
-	// 並び替え
-	var items []bson.M
-	items = append(items, jmaItems...)
-	items = append(items, userquakeItems...)
-	sort.SliceStable(items, func(i, j int) bool {
-		return items[i]["time"].(string) > items[j]["time"].(string)
-	})
-
-	html, err := renderer.RenderIndex(items)
+	html, err := s.renderIndex(r.Context(), page, ok)
 	if err != nil {
 		log.Printf("Render error: %v\n", err)
-		ResponseError(w, http.StatusNotFound, "Not found")
+		ResponseError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
 	// w.Header().Set("Cache-Control", "no-cache")
-	w.Write([]byte(html))
+	w.Write(html)
 }
 
-// 地震情報・津波予報・緊急地震速報（警報）
-func (s *Service) findJmas(time string) ([]bson.M, error) {
-	opts := options.FindOptions{Sort: bson.D{{"$natural", -1}}}
-	cursor, err := s.Whole.Find(
-		context.TODO(),
-		bson.M{
-			"code": bson.M{"$in": bson.A{551, 552, 556}},
-			"time": bson.M{"$gte": time},
-		}, &opts)
+// paged が false なら直近 72 時間の先頭ページを描画する
+func (s *Service) renderIndex(ctx context.Context, page repository.Page, paged bool) ([]byte, error) {
+	threeDaysAgo := time.Now().In(model.JST).Add(time.Hour * -72).Format("2006/01/02 15:04:05")
+	if !paged {
+		page = repository.Page{Since: threeDaysAgo}
+	}
+
+	items, more, err := s.findIndexItems(ctx, page)
 	if err != nil {
 		return nil, err
 	}
+	s.Correlator.Annotate(ctx, items)
 
-	var items []bson.M
-	cursor.All(context.TODO(), &items)
+	// ページ送りのリンク
+	older, newer := pageLinks(page, items, more, "./", nil)
+	if !paged {
+		older = pageLink("./", nil, "before", repository.Cursor{Time: threeDaysAgo})
+		if len(items) > 0 {
+			older = pageLink("./", nil, "before", repository.CursorOf(items[len(items)-1]))
+		}
+	}
 
-	return items, nil
+	html, err := renderer.RenderIndex(items, older, newer)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(html), nil
 }
 
-// 地震感知情報
-func (s *Service) findUserquakes(time string) ([]bson.M, error) {
-	opts := options.FindOptions{Sort: bson.D{{"$natural", -1}}}
-	cursor, err := s.Whole.Find(
-		context.TODO(),
-		bson.M{
-			"code":       9611,
-			"confidence": bson.M{"$gt": 0.9},
-			"time":       bson.M{"$gte": time},
-		}, &opts)
+// 地震情報・津波予報・緊急地震速報（警報）と地震感知情報をまとめて新しい順に返す。
+// Limit を超える分があれば more を true にする
+func (s *Service) findIndexItems(ctx context.Context, page repository.Page) (items []bson.M, more bool, err error) {
+	query := page
+	if query.Limit > 0 {
+		query.Limit++
+	}
+
+	// 地震情報・津波予報・緊急地震速報（警報）
+	jmaItems, err := s.Repository.FindJmas(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	var items []bson.M
-	cursor.All(context.TODO(), &items)
+	// 地震感知情報
+	userquakeItems, err := s.Repository.FindUserquakes(ctx, query)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 並び替え
+	items = append(items, jmaItems...)
+	items = append(items, userquakeItems...)
+	repository.SortNewest(items)
 
-	// グループ化して除去する必要がある
-	var uniqueItems []bson.M
-	startedAt := make(map[string]bool)
-	for _, item := range items {
-		if _, ok := startedAt[item["started_at"].(string)]; !ok {
-			startedAt[item["started_at"].(string)] = true
-			item["time"] = item["started_at"]
-			uniqueItems = append(uniqueItems, item)
+	if page.Limit > 0 && len(items) > page.Limit {
+		more = true
+		if page.After != nil {
+			items = items[len(items)-page.Limit:]
+		} else {
+			items = items[:page.Limit]
 		}
 	}
 
-	return uniqueItems, nil
+	return items, more, nil
+}
+
+// before / after パラメータからページを作る。どちらもなければ ok は false
+func cursorPage(r *http.Request) (page repository.Page, ok bool, err error) {
+	if before := r.URL.Query().Get("before"); before != "" {
+		c, err := repository.ParseCursor(before)
+		if err != nil {
+			return page, false, err
+		}
+		return repository.Page{Before: c, Limit: pageSize}, true, nil
+	}
+	if after := r.URL.Query().Get("after"); after != "" {
+		c, err := repository.ParseCursor(after)
+		if err != nil {
+			return page, false, err
+		}
+		return repository.Page{After: c, Limit: pageSize}, true, nil
+	}
+	return page, false, nil
+}
+
+// ページ送りのリンク。first は先頭ページ、query は引き継ぐ検索条件
+func pageLinks(page repository.Page, items []bson.M, more bool, first string, query url.Values) (older, newer string) {
+	switch {
+	case page.Before != nil:
+		if more {
+			older = pageLink(first, query, "before", repository.CursorOf(items[len(items)-1]))
+		}
+		newer = pageLink(first, query, "", repository.Cursor{})
+		if len(items) > 0 {
+			newer = pageLink(first, query, "after", repository.CursorOf(items[0]))
+		}
+	case page.After != nil:
+		newer = pageLink(first, query, "", repository.Cursor{})
+		if more {
+			newer = pageLink(first, query, "after", repository.CursorOf(items[0]))
+		}
+		older = pageLink(first, query, "before", *page.After)
+		if len(items) > 0 {
+			older = pageLink(first, query, "before", repository.CursorOf(items[len(items)-1]))
+		}
+	default:
+		if more {
+			older = pageLink(first, query, "before", repository.CursorOf(items[len(items)-1]))
+		}
+	}
+	return older, newer
+}
+
+func pageLink(path string, query url.Values, key string, c repository.Cursor) string {
+	q := url.Values{}
+	for k, v := range query {
+		if k != "before" && k != "after" {
+			q[k] = v
+		}
+	}
+	if key != "" {
+		q.Set(key, c.String())
+	}
+	if len(q) == 0 {
+		return path
+	}
+	return path + "?" + q.Encode()
 }
@@ -3,36 +3,132 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/p2pquake/web-client/model"
 	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (s *Service) ItemHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Vary", "Accept")
+	if wantsJSON(r) {
+		s.EventHandler(w, r)
+		return
+	}
+
 	id := r.PathValue("id")
-	oid, err := primitive.ObjectIDFromHex(id)
+	item, err := s.Repository.FindByID(r.Context(), id)
 	if err != nil {
+		if !errors.Is(err, repository.ErrInvalidID) {
+			log.Printf("Find error: %v\n", err)
+		}
 		ResponseError(w, http.StatusNotFound, "Not found")
 		return
 	}
-	filters := bson.M{"_id": oid}
-	opts := options.FindOneOptions{}
 
-	result := s.Whole.FindOne(context.TODO(), filters, &opts)
-	if result.Err() != nil {
+	s.Correlator.Annotate(r.Context(), []bson.M{item})
+
+	// 関連する情報が探せなくても本体は表示する
+	e, err := s.findEvent(r.Context(), item)
+	if err != nil {
+		log.Printf("Event error: %v\n", err)
+	}
+
+	html, err := renderer.RenderItem(item, s.findBulletins(r.Context(), item), e)
+	if err != nil {
+		log.Printf("Render error: %v\n", err)
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	// w.Header().Set("Cache-Control", "no-cache")
+	w.Write([]byte(html))
+}
+
+// 1 件を JSON で返す。{id}.geojson なら GeoJSON で返す
+func (s *Service) EventHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id, ok := strings.CutSuffix(id, ".geojson"); ok {
+		s.eventGeoJSON(w, r, id)
+		return
+	}
+
+	item, err := s.Repository.FindByID(r.Context(), id)
+	if errors.Is(err, repository.ErrInvalidID) {
+		ResponseJSONError(w, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		ResponseJSONError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if err != nil {
 		log.Printf("Find error: %v\n", err)
+		ResponseJSONError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	e, err := toAPIEvent(item)
+	if err != nil {
+		log.Printf("Convert error: %v\n", err)
+		ResponseJSONError(w, http.StatusInternalServerError, "Convert error")
+		return
+	}
+
+	ResponseJSON(w, http.StatusOK, e)
+}
+
+// Accept で HTML より JSON が優先されているか
+func wantsJSON(r *http.Request) bool {
+	jsonQ, htmlQ := -1.0, -1.0
+	for i, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		// 同じ q なら先に書かれたものを優先する
+		q -= float64(i) * 1e-6
+
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		case "text/html":
+			htmlQ = max(htmlQ, q)
+		}
+	}
+	return jsonQ > 0 && jsonQ > htmlQ
+}
+
+func (s *Service) SourceHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	item, err := s.Repository.FindByID(r.Context(), id)
+	if err != nil {
+		if !errors.Is(err, repository.ErrInvalidID) {
+			log.Printf("Find error: %v\n", err)
+		}
 		ResponseError(w, http.StatusNotFound, "Not found")
 		return
 	}
 
-	var item bson.M
-	result.Decode(&item)
+	bulletins := s.findBulletins(r.Context(), item)
+	if len(bulletins) == 0 {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
 
-	html, err := renderer.RenderItem(item)
+	html, err := renderer.RenderBulletins(item, bulletins)
 	if err != nil {
 		log.Printf("Render error: %v\n", err)
 		ResponseError(w, http.StatusNotFound, "Not found")
@@ -40,31 +136,41 @@ func (s *Service) ItemHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
-	// w.Header().Set("Cache-Control", "no-cache")
 	w.Write([]byte(html))
 }
 
+// 元になった気象庁の電文。見つからない場合やエラーの場合は空
+func (s *Service) findBulletins(ctx context.Context, item bson.M) []bson.M {
+	titles, reportTime, ok := model.BulletinQuery(item)
+	if !ok {
+		return nil
+	}
+
+	bulletins, err := s.Repository.FindBulletins(ctx, titles, reportTime)
+	if err != nil {
+		log.Printf("Find bulletins error: %v\n", err)
+		return nil
+	}
+	return bulletins
+}
+
 func (s *Service) TimeseriesHandler(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	log.Printf("TimeseriesHandler called with ID: %s", id)
-	
+
 	if id == "" {
 		log.Printf("Empty ID received")
 		ResponseError(w, http.StatusBadRequest, "Empty ID")
 		return
 	}
-	
-	// 指定されたIDに対応するstarted_atを取得
-	oid, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
+
+	// 最初のレコードを取得してstarted_atを確認
+	firstItem, err := s.Repository.FindByID(r.Context(), id)
+	if errors.Is(err, repository.ErrInvalidID) {
 		log.Printf("Invalid ObjectID format: %s, error: %v", id, err)
 		ResponseError(w, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
-
-	// 最初のレコードを取得してstarted_atを確認
-	var firstItem bson.M
-	err = s.Whole.FindOne(context.TODO(), bson.M{"_id": oid}).Decode(&firstItem)
 	if err != nil {
 		log.Printf("Item not found for ID: %s, error: %v", id, err)
 		ResponseError(w, http.StatusNotFound, "Item not found")
@@ -73,7 +179,7 @@ func (s *Service) TimeseriesHandler(w http.ResponseWriter, r *http.Request) {
 
 	code, ok := firstItem["code"]
 	log.Printf("Found item with code: %v (type: %T)", code, code)
-	
+
 	// コードの型に応じて比較
 	var codeInt int
 	switch v := code.(type) {
@@ -90,13 +196,13 @@ func (s *Service) TimeseriesHandler(w http.ResponseWriter, r *http.Request) {
 		ResponseError(w, http.StatusBadRequest, "Invalid code type")
 		return
 	}
-	
+
 	if !ok || codeInt != 9611 {
 		log.Printf("Not a userquake event, code: %v (int: %d)", code, codeInt)
 		ResponseError(w, http.StatusBadRequest, "Not a userquake event")
 		return
 	}
-	
+
 	log.Printf("Confirmed userquake event with code: %d", codeInt)
 
 	startedAt, ok := firstItem["started_at"].(string)
@@ -108,26 +214,13 @@ func (s *Service) TimeseriesHandler(w http.ResponseWriter, r *http.Request) {
 	log.Printf("Found started_at: %s", startedAt)
 
 	// 同じstarted_atを持つ全てのレコードを取得
-	opts := options.FindOptions{Sort: bson.D{{"updated_at", 1}}}
-	cursor, err := s.Whole.Find(
-		context.TODO(),
-		bson.M{
-			"code":       9611,
-			"started_at": startedAt,
-		}, &opts)
+	items, err := s.Repository.FindTimeseries(r.Context(), startedAt)
 	if err != nil {
 		log.Printf("Database find error: %v", err)
 		ResponseError(w, http.StatusInternalServerError, "Database error")
 		return
 	}
 
-	var items []bson.M
-	if err = cursor.All(context.TODO(), &items); err != nil {
-		log.Printf("Database cursor error: %v", err)
-		ResponseError(w, http.StatusInternalServerError, "Database error")
-		return
-	}
-
 	log.Printf("Found %d timeseries items for started_at: %s", len(items), startedAt)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(items)
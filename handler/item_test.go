@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWantsJSON(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "*/*", want: false},
+		{accept: "application/json", want: true},
+		{accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", want: false},
+		{accept: "application/json, text/html", want: true},
+		{accept: "text/html, application/json", want: false},
+		{accept: "text/html;q=0.5, application/json", want: true},
+		{accept: "application/json;q=0.5, text/html;q=0.9", want: false},
+		{accept: "Application/JSON", want: true},
+		{accept: "application/json;q=0", want: false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/x", nil)
+		r.Header.Set("Accept", tt.accept)
+		if got := wantsJSON(r); got != tt.want {
+			t.Errorf("wantsJSON(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestEventHandler(t *testing.T) {
+	s := &Service{Repository: apiFixtures()}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{id}", s.ItemHandler)
+	mux.HandleFunc("GET /api/v1/events/{id}", s.EventHandler)
+
+	tests := []struct {
+		name   string
+		path   string
+		accept string
+		status int
+		json   bool
+	}{
+		{name: "api", path: "/api/v1/events/" + oid(1).Hex(), status: http.StatusOK, json: true},
+		{name: "api not found", path: "/api/v1/events/" + oid(9).Hex(), status: http.StatusNotFound, json: true},
+		{name: "api invalid id", path: "/api/v1/events/xyz", status: http.StatusBadRequest, json: true},
+		{name: "negotiated json", path: "/" + oid(1).Hex(), accept: "application/json", status: http.StatusOK, json: true},
+		{name: "negotiated not found", path: "/" + oid(9).Hex(), accept: "application/json", status: http.StatusNotFound, json: true},
+		{name: "html", path: "/" + oid(1).Hex(), accept: "text/html", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			isJSON := strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+			if isJSON != tt.json {
+				t.Fatalf("Content-Type = %q", w.Header().Get("Content-Type"))
+			}
+			if !tt.json || tt.status != http.StatusOK {
+				return
+			}
+
+			var e struct {
+				ID   string `json:"id"`
+				Code int    `json:"code"`
+				Time string `json:"time"`
+				Data struct {
+					Hypocenter string `json:"hypocenter"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &e); err != nil {
+				t.Fatal(err)
+			}
+			if e.ID != oid(1).Hex() || e.Code != 551 || e.Time != "2026-10-01T10:00:00+09:00" || !strings.HasPrefix(e.Data.Hypocenter, "石川県能登地方") {
+				t.Errorf("event = %+v", e)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMain(m *testing.M) {
+	if err := renderer.Load("../template"); err != nil {
+		log.Fatalf("Template error: %v\n", err)
+	}
+	os.Exit(m.Run())
+}
+
+// 一覧を取得した回数を数える
+type countingRepository struct {
+	repository.Repository
+	finds atomic.Int32
+}
+
+func (c *countingRepository) FindJmas(ctx context.Context, page repository.Page) ([]bson.M, error) {
+	c.finds.Add(1)
+	return c.Repository.FindJmas(ctx, page)
+}
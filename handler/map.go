@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/repository"
+	"github.com/p2pquake/web-client/svgmap"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// /map/hypocenter/{id}.svg
+func (s *Service) HypocenterMapHandler(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.findMapItem(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := model.Convert(item)
+	if err != nil {
+		log.Printf("Convert error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	eq, ok := data.(*model.Earthquake)
+	if !ok {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	responseSVG(w, svgmap.Hypocenter(eq), mapCacheTTL)
+}
+
+// /map/intensity/{id}.svg
+func (s *Service) IntensityMapHandler(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.findMapItem(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := model.Convert(item)
+	if err != nil {
+		log.Printf("Convert error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	eq, ok := data.(*model.Earthquake)
+	if !ok {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	responseSVG(w, svgmap.PrefectureIntensity(eq), mapCacheTTL)
+}
+
+// /map/tsunami/{id}.svg
+func (s *Service) TsunamiMapHandler(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.findMapItem(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := model.Convert(item)
+	if err != nil {
+		log.Printf("Convert error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	t, ok := data.(*model.Tsunami)
+	if !ok {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	responseSVG(w, svgmap.Tsunami(t), mapCacheTTL)
+}
+
+// /map/userquake/{id}.svg
+func (s *Service) UserquakeMapHandler(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.findMapItem(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := model.Convert(item)
+	if err != nil {
+		log.Printf("Convert error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	uq, ok := data.(*model.Userquake)
+	if !ok {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	// 同じ started_at の時系列で範囲を揃える
+	startedAt, _ := item["started_at"].(string)
+	items, err := s.Repository.FindTimeseries(r.Context(), startedAt)
+	if err != nil {
+		log.Printf("Find error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	uqs := []*model.Userquake{uq}
+	for _, item := range items {
+		if v, err := model.ToUserquake(item); err == nil {
+			uqs = append(uqs, v)
+		}
+	}
+
+	responseSVG(w, svgmap.Userquake(uq, svgmap.UserquakeBounds(uqs)), userquakeMapCacheTTL)
+}
+
+// {id}.svg の情報を探す。見つからなければエラーを返して ok は false
+func (s *Service) findMapItem(w http.ResponseWriter, r *http.Request) (bson.M, bool) {
+	id, ok := strings.CutSuffix(r.PathValue("file"), ".svg")
+	if !ok {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return nil, false
+	}
+
+	item, err := s.Repository.FindByID(r.Context(), id)
+	if errors.Is(err, repository.ErrInvalidID) {
+		ResponseError(w, http.StatusBadRequest, "Invalid ID format")
+		return nil, false
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Find error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, "Database error")
+		return nil, false
+	}
+	return item, true
+}
+
+const (
+	// 地震情報・津波予報は後から変わらないので長めにキャッシュさせる
+	mapCacheTTL = 24 * time.Hour
+	// 地震感知情報は同じ時系列に新しい情報が届くと表示範囲が変わる
+	userquakeMapCacheTTL = time.Minute
+)
+
+func responseSVG(w http.ResponseWriter, svg []byte, ttl time.Duration) {
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(ttl.Seconds())))
+	w.Write(svg)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMapCacheControl(t *testing.T) {
+	s := &Service{Repository: apiFixtures()}
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		id      byte
+		want    string
+	}{
+		{name: "hypocenter", handler: s.HypocenterMapHandler, id: 1, want: "public, max-age=86400"},
+		// 同じ時系列の情報が増えると範囲が変わる
+		{name: "userquake", handler: s.UserquakeMapHandler, id: 3, want: "public, max-age=60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.SetPathValue("file", oid(tt.id).Hex()+".svg")
+			w := httptest.NewRecorder()
+			tt.handler(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d", w.Code)
+			}
+			if got := w.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
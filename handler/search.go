@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
+)
+
+func (s *Service) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, page, err := parseSearch(q)
+	if err != nil {
+		ResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if p, ok, err := cursorPage(r); err != nil {
+		ResponseError(w, http.StatusBadRequest, "Invalid cursor")
+		return
+	} else if ok {
+		p.Since, p.Until = page.Since, page.Until
+		page = p
+	}
+
+	query := page
+	query.Limit++
+	items, err := s.Repository.SearchEarthquakes(r.Context(), filter, query)
+	if err != nil {
+		log.Printf("Search error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	more := len(items) > page.Limit
+	if more {
+		if page.After != nil {
+			items = items[len(items)-page.Limit:]
+		} else {
+			items = items[:page.Limit]
+		}
+	}
+	older, newer := pageLinks(page, items, more, "./search", q)
+
+	data, err := renderer.ConvertAll(items)
+	if err != nil {
+		log.Printf("Render error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	html, err := renderer.RenderSearch(renderer.Search{
+		Query: q,
+		Items: data,
+		Older: older,
+		Newer: newer,
+	})
+	if err != nil {
+		log.Printf("Render error: %v\n", err)
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	w.Write([]byte(html))
+}
+
+// 検索条件のパラメータを読み取る
+func parseSearch(q url.Values) (repository.EarthquakeFilter, repository.Page, error) {
+	filter, page, err := repository.ParseEarthquakeQuery(q)
+	page.Limit = pageSize
+	return filter, page, err
+}
+
+func intParam(q url.Values, key string) (int, error) {
+	v := q.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.New("invalid " + key)
+	}
+	return i, nil
+}
+
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -3,13 +3,19 @@ package handler
 import (
 	"net/http"
 
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/p2pquake/web-client/event"
+	"github.com/p2pquake/web-client/repository"
+	"github.com/p2pquake/web-client/stats"
+	"github.com/p2pquake/web-client/stream"
 )
 
 type Service struct {
-	Client *mongo.Client
-	Whole  *mongo.Collection
-	Jma    *mongo.Collection
+	Repository repository.Repository
+	Hub        *stream.Hub
+	Snapshot   *Snapshot              // nil なら毎回描画する
+	Correlator *event.Correlator      // nil なら地震感知情報と地震情報の対応を調べない
+	Events     *Cache[*event.Event]   // 同じ地震の情報のまとめ（情報の ID ごと）。nil なら毎回探す
+	Stats      *Cache[*stats.Summary] // 統計の集計結果（条件ごと）。nil なら毎回集計する
 }
 
 func ResponseError(w http.ResponseWriter, code int, message string) {
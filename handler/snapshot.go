@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/p2pquake/web-client/repository"
+	"github.com/p2pquake/web-client/stream"
+)
+
+// 新しい情報が続けて届いたときにまとめて描き直すための待ち時間
+const snapshotDebounce = 500 * time.Millisecond
+
+// 事前に描画したトップページ。描き直すたびに丸ごと差し替える
+type Snapshot struct {
+	html atomic.Pointer[[]byte]
+}
+
+func (s *Snapshot) Load() []byte {
+	if p := s.html.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+func (s *Snapshot) Store(html []byte) {
+	s.html.Store(&html)
+}
+
+// ctx が終わるまで、新しい情報の到着時と interval ごとにトップページを描き直す
+func (s *Service) RunSnapshot(ctx context.Context, interval time.Duration) {
+	s.rebuildSnapshot(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		_, events, cancel := s.Hub.Subscribe("")
+		s.watchSnapshot(ctx, events, ticker.C)
+		cancel()
+	}
+}
+
+// events が閉じられる（受信が追いつかず切断される）まで描き直しを続ける
+func (s *Service) watchSnapshot(ctx context.Context, events <-chan stream.Event, tick <-chan time.Time) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			s.rebuildSnapshot(ctx)
+		case _, ok := <-events:
+			if !ok {
+				s.rebuildSnapshot(ctx)
+				return
+			}
+
+			// 続けて届いた分をまとめる
+			timer := time.NewTimer(snapshotDebounce)
+		drain:
+			for {
+				select {
+				case _, ok := <-events:
+					if !ok {
+						break drain
+					}
+				case <-timer.C:
+					break drain
+				}
+			}
+			timer.Stop()
+			s.rebuildSnapshot(ctx)
+		}
+	}
+}
+
+func (s *Service) rebuildSnapshot(ctx context.Context) {
+	html, err := s.renderIndex(ctx, repository.Page{}, false)
+	if err != nil {
+		// 失敗した場合は前回のものを使い続ける
+		log.Printf("Snapshot error: %v\n", err)
+		return
+	}
+	s.Snapshot.Store(html)
+}
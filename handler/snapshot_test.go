@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/repository"
+	"github.com/p2pquake/web-client/stream"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSnapshotSwap(t *testing.T) {
+	var s Snapshot
+	if s.Load() != nil {
+		t.Fatal("Load() before Store is not nil")
+	}
+
+	first := []byte("first")
+	s.Store(first)
+	old := s.Load()
+	s.Store([]byte("second"))
+
+	// 読み出し済みのものは差し替え後も変わらない
+	if string(old) != "first" || string(s.Load()) != "second" {
+		t.Errorf("Load() = %q then %q, want first then second", old, s.Load())
+	}
+}
+
+func TestWatchSnapshot(t *testing.T) {
+	memory := repository.NewMemory()
+	repo := &countingRepository{Repository: memory}
+	s := &Service{Repository: repo, Snapshot: &Snapshot{}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan stream.Event)
+	tick := make(chan time.Time)
+	done := make(chan struct{})
+	go func() {
+		s.watchSnapshot(ctx, events, tick)
+		close(done)
+	}()
+
+	// 続けて届いた情報は 1 回の描き直しにまとめる
+	id := primitive.NewObjectID()
+	memory.Insert(bson.M{"_id": id, "code": 552, "time": time.Now().In(model.JST).Format("2006/01/02 15:04:05.000"), "cancelled": true})
+	for i := 0; i < 3; i++ {
+		events <- stream.Event{}
+	}
+	if n := repo.finds.Load(); n != 0 {
+		t.Errorf("rebuilt %d times before the debounce, want 0", n)
+	}
+	waitFor(t, func() bool { return repo.finds.Load() == 1 })
+	if !bytes.Contains(s.Snapshot.Load(), []byte(id.Hex())) {
+		t.Error("snapshot does not contain the new item")
+	}
+
+	tick <- time.Now()
+	waitFor(t, func() bool { return repo.finds.Load() == 2 })
+
+	// 切断されたら描き直して戻る
+	close(events)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watchSnapshot did not return after events closed")
+	}
+	if n := repo.finds.Load(); n != 3 {
+		t.Errorf("rebuilt %d times, want 3", n)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * snapshotDebounce)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
+	"github.com/p2pquake/web-client/stats"
+)
+
+// 期間の指定がなければ直近 30 日
+const statsDefaultDays = 30
+
+// 集計結果を覚えておく時間（グラフの Cache-Control と同じ）
+const StatsCacheTTL = 5 * time.Minute
+
+// 統計の対象にする条件（期間・集計の単位・震源・震度観測のある都道府県）
+type statsQuery struct {
+	From, To   time.Time
+	Interval   string
+	Hypocenter string
+	Prefs      []string
+}
+
+// 条件と、期間を集計の単位で区切ったときの各期間の始まり
+func parseStatsQuery(q url.Values) (statsQuery, []time.Time, error) {
+	// 日付は日本時間で区切る
+	now := time.Now().In(model.JST)
+	sq := statsQuery{
+		To:         time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, model.JST),
+		Interval:   q.Get("interval"),
+		Hypocenter: strings.TrimSpace(q.Get("hypocenter")),
+		Prefs:      nonEmpty(q["pref"]),
+	}
+	if sq.Interval == "" {
+		sq.Interval = stats.Day
+	}
+
+	if to := q.Get("to"); to != "" {
+		t, err := time.ParseInLocation("2006-01-02", to, model.JST)
+		if err != nil {
+			return sq, nil, errors.New("invalid to")
+		}
+		sq.To = t
+	}
+	sq.From = sq.To.AddDate(0, 0, 1-statsDefaultDays)
+	if from := q.Get("from"); from != "" {
+		t, err := time.ParseInLocation("2006-01-02", from, model.JST)
+		if err != nil {
+			return sq, nil, errors.New("invalid from")
+		}
+		sq.From = t
+	}
+	if sq.From.After(sq.To) {
+		return sq, nil, errors.New("from is after to")
+	}
+
+	starts, err := stats.PeriodStarts(sq.From, sq.To, sq.Interval)
+	if errors.Is(err, stats.ErrInvalidInterval) {
+		return sq, nil, errors.New("invalid interval")
+	}
+	if errors.Is(err, stats.ErrTooManyPeriods) {
+		return sq, nil, errors.New("period is too long for the interval")
+	}
+	return sq, starts, err
+}
+
+// 各地の震度に関する情報（DetailScale）を集計する。
+// ページとグラフ 3 枚で同じ条件の集計が続けて求められるので、条件ごとにしばらく覚えておく
+func (s *Service) summarizeStats(ctx context.Context, sq statsQuery, starts []time.Time) (*stats.Summary, error) {
+	key := sq.encode()
+	if summary, ok := s.Stats.Get(key); ok {
+		return summary, nil
+	}
+
+	filter := repository.EarthquakeFilter{
+		Hypocenter: sq.Hypocenter,
+		Prefs:      sq.Prefs,
+		IssueTypes: []string{"DetailScale"},
+	}
+	page := repository.Page{
+		Since: starts[0].Format("2006/01/02 15:04:05"),
+		Until: sq.To.AddDate(0, 0, 1).Format("2006/01/02 15:04:05"),
+	}
+	raw, err := s.Repository.EarthquakeStats(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := stats.Summarize(raw, starts, sq.Interval)
+	summary.From = starts[0].Format("2006-01-02")
+	summary.To = sq.To.Format("2006-01-02")
+	s.Stats.Set(key, summary)
+	return summary, nil
+}
+
+// グラフの画像に同じ条件を渡すためのクエリ文字列
+func (sq statsQuery) encode() string {
+	q := url.Values{}
+	q.Set("from", sq.From.Format("2006-01-02"))
+	q.Set("to", sq.To.Format("2006-01-02"))
+	q.Set("interval", sq.Interval)
+	if sq.Hypocenter != "" {
+		q.Set("hypocenter", sq.Hypocenter)
+	}
+	for _, pref := range sq.Prefs {
+		q.Add("pref", pref)
+	}
+	return q.Encode()
+}
+
+// /stats
+func (s *Service) StatsHandler(w http.ResponseWriter, r *http.Request) {
+	sq, starts, err := parseStatsQuery(r.URL.Query())
+	if err != nil {
+		ResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, err := s.summarizeStats(r.Context(), sq, starts)
+	if err != nil {
+		log.Printf("Stats error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	html, err := renderer.RenderStats(renderer.Stats{
+		Query:      r.URL.Query(),
+		Summary:    summary,
+		ChartQuery: template.URL(sq.encode()),
+	})
+	if err != nil {
+		log.Printf("Render error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	w.Write([]byte(html))
+}
+
+// /api/v1/stats
+func (s *Service) StatsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	sq, starts, err := parseStatsQuery(r.URL.Query())
+	if err != nil {
+		ResponseJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, err := s.summarizeStats(r.Context(), sq, starts)
+	if err != nil {
+		log.Printf("Stats error: %v\n", err)
+		ResponseJSONError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	ResponseJSON(w, http.StatusOK, summary)
+}
+
+// /stats/{chart}.svg（scale・magnitude・depth）
+func (s *Service) StatsChartHandler(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("chart"), ".svg")
+	if !ok || (name != "scale" && name != "magnitude" && name != "depth") {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	sq, starts, err := parseStatsQuery(r.URL.Query())
+	if err != nil {
+		ResponseError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, err := s.summarizeStats(r.Context(), sq, starts)
+	if err != nil {
+		log.Printf("Stats error: %v\n", err)
+		ResponseError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var svg []byte
+	switch name {
+	case "scale":
+		svg = stats.ScaleChart(summary)
+	case "magnitude":
+		svg = stats.HistogramChart("マグニチュード別の地震の回数", summary.Magnitudes)
+	case "depth":
+		svg = stats.HistogramChart("深さ別の地震の回数", summary.Depths)
+	}
+
+	// 地図と違って新しい情報で変わるので短くする
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Write(svg)
+}
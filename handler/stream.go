@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/p2pquake/web-client/stream"
+)
+
+// 接続維持のための heartbeat を送る間隔
+const heartbeatInterval = 15 * time.Second
+
+func (s *Service) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		ResponseError(w, http.StatusInternalServerError, "Streaming unsupported")
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+	replay, events, cancel := s.Hub.Subscribe(lastEventID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, "retry: 3000\n\n")
+
+	for _, e := range replay {
+		if _, err := e.WriteTo(w); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if _, err := e.WriteTo(w); err != nil {
+				return
+			}
+			flusher.Flush()
+		case now := <-heartbeat.C:
+			if _, err := stream.Heartbeat(now).WriteTo(w); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/p2pquake/web-client/event"
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// /tsunami/{id}: id の津波情報を含む一連の津波情報の推移を表示する
+func (s *Service) TsunamiTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	item, err := s.Repository.FindByID(r.Context(), id)
+	if err != nil {
+		if !errors.Is(err, repository.ErrInvalidID) && !errors.Is(err, repository.ErrNotFound) {
+			log.Printf("Find error: %v\n", err)
+		}
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if model.ToInt(item["code"]) != 552 {
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	var candidates []bson.M
+	if t := model.ParseTime(event.ItemTime(item)); t != nil {
+		candidates, err = s.Repository.FindJmas(r.Context(), repository.Page{
+			Since: t.Add(-event.TsunamiSearchWindow).Format("2006/01/02 15:04:05"),
+			Until: t.Add(event.TsunamiSearchWindow).Format("2006/01/02 15:04:05"),
+			Codes: []int{552},
+		})
+		if err != nil {
+			log.Printf("Find error: %v\n", err)
+			ResponseError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+	}
+
+	html, err := renderer.RenderTsunamiTimeline(event.TsunamiSequence(item, candidates), id)
+	if err != nil {
+		log.Printf("Render error: %v\n", err)
+		ResponseError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	w.Write([]byte(html))
+}
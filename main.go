@@ -7,36 +7,67 @@ import (
 	"os"
 	"time"
 
+	"github.com/p2pquake/web-client/event"
 	"github.com/p2pquake/web-client/handler"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"github.com/p2pquake/web-client/renderer"
+	"github.com/p2pquake/web-client/repository"
+	"github.com/p2pquake/web-client/stats"
+	"github.com/p2pquake/web-client/stream"
 )
 
 func main() {
-	mongodbUrl := os.Getenv("MONGODB_URL")
-	mongodbDatabase := os.Getenv("DATABASE")
-	mongodbCollection := os.Getenv("COLLECTION")
-
-	opts := options.Client().ApplyURI(mongodbUrl)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
 	log.Printf("P2PQuake web client")
-	log.Printf("Database %v, Collection: %v\n", mongodbDatabase, mongodbCollection)
 
-	client, err := mongo.Connect(ctx, opts)
+	if err := renderer.Load("./template"); err != nil {
+		log.Fatalf("Template error: %v", err)
+	}
+	if os.Getenv("TEMPLATE_RELOAD") != "" {
+		log.Printf("Template reload enabled")
+		go renderer.Watch(context.Background())
+	}
+
+	repo, closeRepo, err := repository.FromEnv()
 	if err != nil {
-		log.Fatalf("MongoDB connect error: %v", err)
+		log.Fatalf("Repository error: %v", err)
 	}
-	defer client.Disconnect(ctx)
+	defer closeRepo()
 
-	whole := client.Database(mongodbDatabase).Collection(mongodbCollection)
-	jma := client.Database(mongodbDatabase).Collection("jma")
-	service := handler.Service{Client: client, Whole: whole, Jma: jma}
+	hub := stream.NewHub(repo)
+	go hub.Run(context.Background())
+	service := handler.Service{
+		Repository: repo,
+		Hub:        hub,
+		Snapshot:   &handler.Snapshot{},
+		Correlator: event.NewCorrelator(repo),
+		Events:     handler.NewCache[*event.Event](handler.EventCacheTTL),
+		Stats:      handler.NewCache[*stats.Summary](handler.StatsCacheTTL),
+	}
+	go service.RunSnapshot(context.Background(), 10*time.Second)
 
 	http.HandleFunc("GET /", service.IndexHandler)
 	http.HandleFunc("GET /{id}", service.ItemHandler)
+	http.HandleFunc("GET /source/{id}", service.SourceHandler)
+	http.HandleFunc("GET /event/{id}", service.EventPageHandler)
+	http.HandleFunc("GET /tsunami/{id}", service.TsunamiTimelineHandler)
 	http.HandleFunc("GET /api/timeseries/{id}", service.TimeseriesHandler)
+	http.HandleFunc("GET /api/stream", service.StreamHandler)
+	http.HandleFunc("GET /api/v1/events", service.EventsHandler)
+	http.HandleFunc("GET /api/v1/events.geojson", service.EventsGeoJSONHandler)
+	http.HandleFunc("GET /api/v1/events/{id}", service.EventHandler)
+	http.HandleFunc("GET /api/v1/stats", service.StatsAPIHandler)
+	http.HandleFunc("GET /search", service.SearchHandler)
+	http.HandleFunc("GET /stats", service.StatsHandler)
+	http.HandleFunc("GET /stats/{chart}", service.StatsChartHandler)
+	http.HandleFunc("GET /export/earthquakes.csv", service.ExportEarthquakesHandler)
+	http.HandleFunc("GET /map/hypocenter/{file}", service.HypocenterMapHandler)
+	http.HandleFunc("GET /map/intensity/{file}", service.IntensityMapHandler)
+	http.HandleFunc("GET /map/tsunami/{file}", service.TsunamiMapHandler)
+	http.HandleFunc("GET /map/userquake/{file}", service.UserquakeMapHandler)
+	http.HandleFunc("GET /feed.atom", service.AtomHandler)
+	http.HandleFunc("GET /feed.rss", service.RSSHandler)
+	http.HandleFunc("GET /archive/{year}", service.ArchiveYearHandler)
+	http.HandleFunc("GET /archive/{year}/{month}", service.ArchiveMonthHandler)
+	http.HandleFunc("GET /archive/{year}/{month}/{day}", service.ArchiveDayHandler)
 	http.Handle("GET /static/", oneDayCache(http.StripPrefix("/static/", http.FileServer(http.Dir("static")))))
 
 	http.ListenAndServe(":8080", nil)
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 気象庁の電文（jma コレクション）
+type Bulletin struct {
+	ObjectID   string
+	Title      string
+	Headline   string
+	ReportTime string
+	EventID    string
+	Body       string
+	IsXML      bool
+}
+
+type BulletinRecord struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	Title      string             `bson:"title"`
+	Headline   string             `bson:"headline"`
+	ReportTime string             `bson:"report_time"`
+	EventID    string             `bson:"event_id"`
+	Body       string             `bson:"body"`
+}
+
+func ToBulletin(data primitive.M) (*Bulletin, error) {
+	var b BulletinRecord
+	bytes, _ := bson.Marshal(data)
+	bson.Unmarshal(bytes, &b)
+
+	return &Bulletin{
+		ObjectID:   b.ID.Hex(),
+		Title:      b.Title,
+		Headline:   b.Headline,
+		ReportTime: format(b.ReportTime),
+		EventID:    b.EventID,
+		Body:       b.Body,
+		IsXML:      strings.HasPrefix(strings.TrimSpace(b.Body), "<"),
+	}, nil
+}
+
+// 元になった電文を探すための条件（電文の表題と発表時刻）
+func BulletinQuery(data primitive.M) (titles []string, reportTime string, ok bool) {
+	var r struct {
+		Issue struct {
+			Time string `bson:"time"`
+			Type string `bson:"type"`
+		} `bson:"issue"`
+	}
+	bytes, _ := bson.Marshal(data)
+	bson.Unmarshal(bytes, &r)
+
+	switch ToInt(data["code"]) {
+	case 551:
+		switch r.Issue.Type {
+		case "ScalePrompt":
+			titles = []string{"震度速報"}
+		case "Destination":
+			titles = []string{"震源に関する情報"}
+		case "ScaleAndDestination", "DetailScale":
+			titles = []string{"震源・震度に関する情報"}
+		case "Foreign":
+			titles = []string{"遠地地震に関する情報"}
+		}
+	case 552:
+		titles = []string{"津波警報・注意報・予報a", "津波警報・注意報・予報"}
+	case 556:
+		titles = []string{"緊急地震速報（警報）"}
+	}
+
+	if len(titles) == 0 || r.Issue.Time == "" {
+		return nil, "", false
+	}
+	return titles, r.Issue.Time, true
+}
@@ -5,7 +5,7 @@ import "go.mongodb.org/mongo-driver/bson"
 func Convert(data bson.M) (interface{}, error) {
 	var result interface{}
 	var err error = nil
-	switch toInt(data["code"]) {
+	switch ToInt(data["code"]) {
 	case 551:
 		result, err = ToEarthquake(data)
 	case 552:
@@ -20,14 +20,17 @@ func Convert(data bson.M) (interface{}, error) {
 	return result, err
 }
 
-func toInt(e interface{}) int {
-	switch e.(type) {
+// bson・JSON から読み込んだ数値（int32・int64・float64 など）を int にする。数値でなければ 0
+func ToInt(e interface{}) int {
+	switch v := e.(type) {
 	case int:
-		return e.(int)
+		return v
 	case int32:
-		return int(e.(int32))
+		return int(v)
 	case int64:
-		return int(e.(int64))
+		return int(v)
+	case float64:
+		return int(v)
 	}
 	return 0
 }
@@ -12,31 +12,38 @@ import (
 )
 
 type Earthquake struct {
-	Raw              string
-	Code             int
-	ObjectID         string
-	MaxScale         string
-	IssueTime        string
-	IssueType        string
-	OccurredTime     string
-	ShortTime        string
-	Hypocenter       string
-	IsEruption       bool
-	FreeFormComments []string
-	Tsunami          string
-	ForeignTsunami   string
-	Points           []PointsByPref
-	PointsByScale    []PointsByScale
+	Raw                string          `json:"-"`
+	Code               int             `json:"code"`
+	ObjectID           string          `json:"id"`
+	MaxScale           string          `json:"maxScale"`
+	MaxScaleCode       int             `json:"maxScaleCode"`
+	IssueTime          string          `json:"issueTime"`
+	IssuedAt           *time.Time      `json:"issuedAt,omitempty"`
+	IssueType          string          `json:"issueType"`
+	OccurredTime       string          `json:"occurredTime"`
+	OccurredAt         *time.Time      `json:"occurredAt,omitempty"`
+	ShortTime          string          `json:"shortTime"`
+	Hypocenter         string          `json:"hypocenter"`
+	HypocenterDetail   Hypocenter      `json:"hypocenterDetail"`
+	IsEruption         bool            `json:"isEruption"`
+	FreeFormComments   []string        `json:"freeFormComments"`
+	Tsunami            string          `json:"tsunami"`
+	TsunamiCode        string          `json:"tsunamiCode"`
+	ForeignTsunami     string          `json:"foreignTsunami"`
+	ForeignTsunamiCode string          `json:"foreignTsunamiCode"`
+	Points             []PointsByPref  `json:"points"`
+	PointsByScale      []PointsByScale `json:"pointsByScale"`
 }
 
 type PointsByPref struct {
-	Pref   string
-	Points []PointsByScale
+	Pref   string          `json:"pref"`
+	Points []PointsByScale `json:"points"`
 }
 
 type PointsByScale struct {
-	Scale  string
-	Points []string
+	Scale     string   `json:"scale"`
+	ScaleCode int      `json:"scaleCode"`
+	Points    []string `json:"points"`
 }
 
 func (ps PointsByScale) PointString() string {
@@ -69,12 +76,13 @@ type Point struct {
 	Scale  int    `bson:"scale"`
 }
 
+// 不明な値は P2P地震情報 の JSON と同じく -1（緯度・経度は -200）
 type Hypocenter struct {
-	Name      string  `bson:"name"`
-	Depth     int     `bson:"depth"`
-	Latitude  float64 `bson:"latitude"`
-	Longitude float64 `bson:"longitude"`
-	Magnitude float64 `bson:"magnitude"`
+	Name      string  `bson:"name" json:"name"`
+	Depth     int     `bson:"depth" json:"depth"`
+	Latitude  float64 `bson:"latitude" json:"latitude"`
+	Longitude float64 `bson:"longitude" json:"longitude"`
+	Magnitude float64 `bson:"magnitude" json:"magnitude"`
 }
 
 func ToEarthquake(data primitive.M) (*Earthquake, error) {
@@ -84,9 +92,7 @@ func ToEarthquake(data primitive.M) (*Earthquake, error) {
 
 	// 「震度5弱以上と推定」の優先度を下げる（震度5弱より低い）
 	for i := 0; i < len(eq.Points); i++ {
-		if eq.Points[i].Scale == 46 {
-			eq.Points[i].Scale = 44
-		}
+		eq.Points[i].Scale = ScaleRank(eq.Points[i].Scale)
 	}
 
 	r := regexp.MustCompile("^((?:余市町|田村市|玉村町|東村山市|武蔵村山市|羽村市|十日町市|上市町|大町市|名古屋中村区|大阪堺市.+?区|下市町|大村市|野々市市|四日市市|廿日市市|大町町|.+?[市区町村]))")
@@ -139,8 +145,9 @@ func ToEarthquake(data primitive.M) (*Earthquake, error) {
 		var pointsByScale []PointsByScale
 		for _, s := range scales {
 			pointsByScale = append(pointsByScale, PointsByScale{
-				Scale:  scale(s),
-				Points: byScale[s],
+				Scale:     scale(s),
+				ScaleCode: scaleCode(s),
+				Points:    byScale[s],
 			})
 		}
 
@@ -164,8 +171,9 @@ func ToEarthquake(data primitive.M) (*Earthquake, error) {
 	var pointsByScale []PointsByScale
 	for _, s := range scales {
 		pointsByScale = append(pointsByScale, PointsByScale{
-			Scale:  scale(s),
-			Points: byScale[s],
+			Scale:     scale(s),
+			ScaleCode: scaleCode(s),
+			Points:    byScale[s],
 		})
 	}
 
@@ -176,24 +184,53 @@ func ToEarthquake(data primitive.M) (*Earthquake, error) {
 	}
 
 	return &Earthquake{
-		Raw:              fmt.Sprintf("%v\n", eq),
-		ObjectID:         eq.ID.Hex(),
-		Code:             551,
-		MaxScale:         scale(eq.Earthquake.MaxScale),
-		IssueType:        eq.Issue.Type,
-		IssueTime:        format(eq.Issue.Time),
-		OccurredTime:     format(eq.Earthquake.Time),
-		ShortTime:        formatShort(eq.Earthquake.Time),
-		Tsunami:          tsunami(eq.Earthquake.DomesticTsunami),
-		ForeignTsunami:   tsunami(eq.Earthquake.ForeignTsunami),
-		Hypocenter:       hypocenter(eq.Earthquake.Hypocenter, isEruption),
-		IsEruption:       isEruption,
-		FreeFormComments: freeFormComments,
-		Points:           pointsByPref,
-		PointsByScale:    pointsByScale,
+		Raw:                fmt.Sprintf("%v\n", eq),
+		ObjectID:           eq.ID.Hex(),
+		Code:               551,
+		MaxScale:           scale(eq.Earthquake.MaxScale),
+		MaxScaleCode:       eq.Earthquake.MaxScale,
+		IssueType:          eq.Issue.Type,
+		IssueTime:          format(eq.Issue.Time),
+		IssuedAt:           ParseTime(eq.Issue.Time),
+		OccurredTime:       format(eq.Earthquake.Time),
+		OccurredAt:         ParseTime(eq.Earthquake.Time),
+		ShortTime:          formatShort(eq.Earthquake.Time),
+		Tsunami:            tsunami(eq.Earthquake.DomesticTsunami),
+		TsunamiCode:        eq.Earthquake.DomesticTsunami,
+		ForeignTsunami:     tsunami(eq.Earthquake.ForeignTsunami),
+		ForeignTsunamiCode: eq.Earthquake.ForeignTsunami,
+		Hypocenter:         hypocenter(eq.Earthquake.Hypocenter, isEruption),
+		HypocenterDetail:   eq.Earthquake.Hypocenter,
+		IsEruption:         isEruption,
+		FreeFormComments:   freeFormComments,
+		Points:             pointsByPref,
+		PointsByScale:      pointsByScale,
 	}, nil
 }
 
+// 震度の値（小さい順、「5弱以上と推定」は除く）
+var ScaleCodes = []int{10, 20, 30, 40, 45, 50, 55, 60, 70}
+
+func ScaleName(s int) string {
+	return scale(s)
+}
+
+// 「5弱以上と推定」（46）を 5弱より低い 44 として扱った、大小の比較用の値
+func ScaleRank(s int) int {
+	if s == 46 {
+		return 44
+	}
+	return s
+}
+
+// 並び替えのために 46 -> 44 に書き換えたものを元の値に戻す
+func scaleCode(s int) int {
+	if s == 44 {
+		return 46
+	}
+	return s
+}
+
 func scale(s int) string {
 	switch s {
 	case 10:
@@ -222,6 +259,19 @@ func scale(s int) string {
 	return "不明"
 }
 
+// 日時の文字列（日本時間）を解釈する。解釈できなければ nil
+func ParseTime(t string) *time.Time {
+	for _, layout := range []string{"2006/01/02 15:04:05.999", "2006/01/02 15:04"} {
+		if s, err := time.ParseInLocation(layout, t, JST); err == nil {
+			return &s
+		}
+	}
+	return nil
+}
+
+// コンテナに tzdata がない場合もあるため固定オフセットを使う
+var JST = time.FixedZone("JST", 9*60*60)
+
 func format(t string) string {
 	s, err := time.Parse("2006/01/02 15:04:05", t)
 	if err != nil {
@@ -291,3 +341,92 @@ func depth(depth int) string {
 	}
 	return fmt.Sprintf("深さ%dkm", depth)
 }
+
+// 前回の地震情報から変わった項目
+type RevisionChange struct {
+	Name   string `json:"name"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// 新たに加わった、または震度が上がった市区町村
+type PointChange struct {
+	Pref      string `json:"pref"`
+	Addr      string `json:"addr"`
+	Scale     string `json:"scale"`
+	ScaleCode int    `json:"scaleCode"`
+	PrevScale string `json:"prevScale,omitempty"` // 新たに加わったものは空
+}
+
+// prev から cur への発生時刻・震源・深さ・マグニチュード・最大震度の変化
+func DiffEarthquake(prev, cur *Earthquake) []RevisionChange {
+	var changes []RevisionChange
+	add := func(name, before, after string) {
+		if before != after {
+			changes = append(changes, RevisionChange{Name: name, Before: before, After: after})
+		}
+	}
+
+	add("発生時刻", prev.OccurredTime, cur.OccurredTime)
+	add("震源", hypocenterName(prev.HypocenterDetail), hypocenterName(cur.HypocenterDetail))
+	add("深さ", revisionDepth(prev.HypocenterDetail), revisionDepth(cur.HypocenterDetail))
+	add("マグニチュード", magnitude(prev.HypocenterDetail), magnitude(cur.HypocenterDetail))
+	add("最大震度", prev.MaxScale, cur.MaxScale)
+	return changes
+}
+
+// 市区町村ごとの震度がある（各地の震度に関する情報）か。震度速報などの地域ごとの震度は含まない
+func (e *Earthquake) HasCityPoints() bool {
+	return e.IssueType == "DetailScale" && len(e.Points) > 0
+}
+
+// prev になかった、または prev より震度が上がった cur の市区町村（cur の並び順）
+func DiffPoints(prev, cur *Earthquake) []PointChange {
+	prevScales := make(map[string]int)
+	for _, p := range prev.Points {
+		for _, s := range p.Points {
+			for _, addr := range s.Points {
+				prevScales[p.Pref+" "+addr] = s.ScaleCode
+			}
+		}
+	}
+
+	var changes []PointChange
+	for _, p := range cur.Points {
+		for _, s := range p.Points {
+			for _, addr := range s.Points {
+				c := PointChange{Pref: p.Pref, Addr: addr, Scale: s.Scale, ScaleCode: s.ScaleCode}
+				prevScale, ok := prevScales[p.Pref+" "+addr]
+				if ok {
+					if ScaleRank(s.ScaleCode) <= ScaleRank(prevScale) {
+						continue
+					}
+					c.PrevScale = ScaleName(prevScale)
+				}
+				changes = append(changes, c)
+			}
+		}
+	}
+	return changes
+}
+
+func hypocenterName(h Hypocenter) string {
+	if h.Name == "" {
+		return "不明"
+	}
+	return h.Name
+}
+
+func revisionDepth(h Hypocenter) string {
+	if h.Name == "" {
+		return "不明"
+	}
+	return depth(h.Depth)
+}
+
+func magnitude(h Hypocenter) string {
+	if h.Magnitude < 0 {
+		return "不明"
+	}
+	return fmt.Sprintf("M%.1f", h.Magnitude)
+}
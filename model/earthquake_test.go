@@ -0,0 +1,85 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func earthquakeOf(issueType string, points ...PointsByPref) *Earthquake {
+	return &Earthquake{IssueType: issueType, Points: points}
+}
+
+func prefPoints(pref string, code int, addrs ...string) PointsByPref {
+	return PointsByPref{Pref: pref, Points: []PointsByScale{{Scale: ScaleName(code), ScaleCode: code, Points: addrs}}}
+}
+
+func TestDiffPoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev, cur *Earthquake
+		want      []PointChange
+	}{
+		{
+			name: "unchanged",
+			prev: earthquakeOf("DetailScale", prefPoints("石川県", 50, "輪島市")),
+			cur:  earthquakeOf("DetailScale", prefPoints("石川県", 50, "輪島市")),
+		},
+		{
+			name: "added",
+			prev: earthquakeOf("DetailScale", prefPoints("石川県", 50, "輪島市")),
+			cur:  earthquakeOf("DetailScale", prefPoints("石川県", 50, "輪島市", "珠洲市")),
+			want: []PointChange{{Pref: "石川県", Addr: "珠洲市", Scale: "5強", ScaleCode: 50}},
+		},
+		{
+			name: "raised",
+			prev: earthquakeOf("DetailScale", prefPoints("石川県", 45, "輪島市")),
+			cur:  earthquakeOf("DetailScale", prefPoints("石川県", 50, "輪島市")),
+			want: []PointChange{{Pref: "石川県", Addr: "輪島市", Scale: "5強", ScaleCode: 50, PrevScale: "5弱"}},
+		},
+		{
+			name: "lowered",
+			prev: earthquakeOf("DetailScale", prefPoints("石川県", 50, "輪島市")),
+			cur:  earthquakeOf("DetailScale", prefPoints("石川県", 45, "輪島市")),
+		},
+		{
+			// 「5弱以上と推定」から 5弱 は上がったものとする
+			name: "estimated to observed",
+			prev: earthquakeOf("DetailScale", prefPoints("石川県", 46, "輪島市")),
+			cur:  earthquakeOf("DetailScale", prefPoints("石川県", 45, "輪島市")),
+			want: []PointChange{{Pref: "石川県", Addr: "輪島市", Scale: "5弱", ScaleCode: 45, PrevScale: "5弱以上と推定"}},
+		},
+		{
+			name: "same name in another prefecture",
+			prev: earthquakeOf("DetailScale", prefPoints("北海道", 30, "森町")),
+			cur:  earthquakeOf("DetailScale", prefPoints("北海道", 30, "森町"), prefPoints("静岡県", 30, "森町")),
+			want: []PointChange{{Pref: "静岡県", Addr: "森町", Scale: "3", ScaleCode: 30}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffPoints(tt.prev, tt.cur)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffPoints() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasCityPoints(t *testing.T) {
+	tests := []struct {
+		eq   *Earthquake
+		want bool
+	}{
+		{earthquakeOf("DetailScale", prefPoints("石川県", 50, "輪島市")), true},
+		{earthquakeOf("ScalePrompt", prefPoints("石川県", 50, "石川県能登")), false},
+		{earthquakeOf("ScaleAndDestination", prefPoints("石川県", 50, "石川県能登")), false},
+		{earthquakeOf("DetailScale"), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.eq.HasCityPoints(); got != tt.want {
+			t.Errorf("HasCityPoints(%s) = %v, want %v", tt.eq.IssueType, got, tt.want)
+		}
+	}
+}
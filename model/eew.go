@@ -2,28 +2,31 @@ package model
 
 import (
 	"strconv"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type EEW struct {
-	Code       int
-	ObjectID   string
-	Serial     int
-	IssueTime  string
-	ShortTime  string
-	Cancelled  bool
-	Hypocenter string
-	Areas      []string
+	Code             int        `json:"code"`
+	ObjectID         string     `json:"id"`
+	Serial           int        `json:"serial"`
+	IssueTime        string     `json:"issueTime"`
+	IssuedAt         *time.Time `json:"issuedAt,omitempty"`
+	ShortTime        string     `json:"shortTime"`
+	OccurredAt       *time.Time `json:"occurredAt,omitempty"`
+	Cancelled        bool       `json:"cancelled"`
+	Hypocenter       string     `json:"hypocenter"`
+	HypocenterDetail Hypocenter `json:"hypocenterDetail"`
+	Areas            []string   `json:"areas"`
 }
 
 type EEWRecord struct {
 	ID         primitive.ObjectID `bson:"_id"`
 	Earthquake struct {
-		Hypocenter struct {
-			Name string `bson:"name"`
-		} `bson:"hypocenter"`
+		OriginTime string     `bson:"originTime"`
+		Hypocenter Hypocenter `bson:"hypocenter"`
 	} `bson:"earthquake"`
 	Issue struct {
 		Time   string `bson:"time"`
@@ -49,14 +52,17 @@ func ToEEW(data primitive.M) (*EEW, error) {
 	}
 
 	return &EEW{
-		ObjectID:   eew.ID.Hex(),
-		Code:       556,
-		Serial:     serial,
-		IssueTime:  formatS(eew.Issue.Time),
-		ShortTime:  formatShort(eew.Issue.Time),
-		Cancelled:  eew.Cancelled,
-		Hypocenter: eew.Earthquake.Hypocenter.Name,
-		Areas:      toAreas(eew.Areas),
+		ObjectID:         eew.ID.Hex(),
+		Code:             556,
+		Serial:           serial,
+		IssueTime:        formatS(eew.Issue.Time),
+		IssuedAt:         ParseTime(eew.Issue.Time),
+		ShortTime:        formatShort(eew.Issue.Time),
+		OccurredAt:       ParseTime(eew.Earthquake.OriginTime),
+		Cancelled:        eew.Cancelled,
+		Hypocenter:       eew.Earthquake.Hypocenter.Name,
+		HypocenterDetail: eew.Earthquake.Hypocenter,
+		Areas:            toAreas(eew.Areas),
 	}, nil
 }
 
@@ -0,0 +1,87 @@
+package model
+
+import (
+	_ "embed"
+	"encoding/json"
+	"math"
+)
+
+// 震度観測点の市区町村（ToEarthquake で Addr から切り出した名前）
+type Place struct {
+	Pref      string  `json:"pref"`
+	Name      string  `json:"name"`
+	Code      string  `json:"code"` // 全国地方公共団体コード（5 桁）
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// 座標の分かる震度観測点
+type ObservedPoint struct {
+	Place
+	Scale     string `json:"scale"`
+	ScaleCode int    `json:"scaleCode"`
+}
+
+// cmd/gazetteer で全国地方公共団体コードと国土数値情報の市区町村役場の位置から作る（README 参照）。
+// 再生成するまでは県庁所在地と、地震の多い地域の一部の市区町村のみ
+//
+//go:embed data/gazetteer.json
+var gazetteerJSON []byte
+
+var gazetteer, placesByName = loadGazetteer(gazetteerJSON)
+
+func loadGazetteer(b []byte) (map[string]Place, map[string][]Place) {
+	var places []Place
+	if err := json.Unmarshal(b, &places); err != nil {
+		panic(err)
+	}
+
+	byPref := make(map[string]Place, len(places))
+	byName := make(map[string][]Place)
+	for _, p := range places {
+		byPref[p.Pref+"/"+p.Name] = p
+		byName[p.Name] = append(byName[p.Name], p)
+	}
+	return byPref, byName
+}
+
+// 都道府県と市区町村名から探す。都道府県で見つからなければ、同名の市区町村がひとつだけの場合に限りそれを返す
+func LookupPlace(pref, name string) (Place, bool) {
+	if p, ok := gazetteer[pref+"/"+name]; ok {
+		return p, true
+	}
+	if places := placesByName[name]; len(places) == 1 {
+		return places[0], true
+	}
+	return Place{}, false
+}
+
+// 座標の分かる震度観測点（震度の大きい順）
+func (e *Earthquake) ObservedPoints() []ObservedPoint {
+	var points []ObservedPoint
+	for _, s := range e.PointsByScale {
+		for _, pref := range e.Points {
+			for _, ps := range pref.Points {
+				if ps.ScaleCode != s.ScaleCode {
+					continue
+				}
+				for _, name := range ps.Points {
+					if p, ok := LookupPlace(pref.Pref, name); ok {
+						points = append(points, ObservedPoint{Place: p, Scale: ps.Scale, ScaleCode: ps.ScaleCode})
+					}
+				}
+			}
+		}
+	}
+	return points
+}
+
+// 2 地点間の距離（km、球面近似）
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371.0
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
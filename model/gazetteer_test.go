@@ -0,0 +1,85 @@
+package model
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestLookupPlace(t *testing.T) {
+	// 同名の市区町村が別の都道府県にある場合
+	gazetteer, placesByName = loadGazetteer([]byte(`[
+		{"pref": "東京都", "name": "府中市", "code": "13206", "latitude": 35.669, "longitude": 139.478},
+		{"pref": "広島県", "name": "府中市", "code": "34208", "latitude": 34.568, "longitude": 133.237},
+		{"pref": "石川県", "name": "輪島市", "code": "17204", "latitude": 37.39, "longitude": 136.899}
+	]`))
+	t.Cleanup(func() { gazetteer, placesByName = loadGazetteer(gazetteerJSON) })
+
+	tests := []struct {
+		name       string
+		pref, city string
+		want       string // code
+	}{
+		{name: "pref and name", pref: "広島県", city: "府中市", want: "34208"},
+		{name: "unique name", pref: "", city: "輪島市", want: "17204"},
+		{name: "other pref unique name", pref: "富山県", city: "輪島市", want: "17204"},
+		{name: "ambiguous name", pref: "", city: "府中市"},
+		{name: "unknown", pref: "石川県", city: "存在しない市"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := LookupPlace(tt.pref, tt.city)
+			if ok != (tt.want != "") || p.Code != tt.want {
+				t.Errorf("LookupPlace() = %+v, %v, want %q", p, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestGazetteer(t *testing.T) {
+	// 埋め込みのデータは都道府県と名前で引ける
+	for _, c := range [][3]string{{"北海道", "函館市", "01202"}, {"石川県", "金沢市", "17201"}} {
+		p, ok := LookupPlace(c[0], c[1])
+		if !ok || p.Code != c[2] || !inJapan(p.Latitude, p.Longitude) {
+			t.Errorf("LookupPlace(%s, %s) = %+v, %v", c[0], c[1], p, ok)
+		}
+	}
+}
+
+func inJapan(latitude, longitude float64) bool {
+	return 20 <= latitude && latitude <= 46 && 122 <= longitude && longitude <= 154
+}
+
+func TestObservedPoints(t *testing.T) {
+	eq := &Earthquake{
+		Points: []PointsByPref{
+			{Pref: "石川県", Points: []PointsByScale{
+				{Scale: "5弱", ScaleCode: 45, Points: []string{"金沢市"}},
+				{Scale: "6強", ScaleCode: 60, Points: []string{"輪島市", "存在しない市"}},
+			}},
+			{Pref: "北海道", Points: []PointsByScale{{Scale: "1", ScaleCode: 10, Points: []string{"函館市"}}}},
+		},
+		PointsByScale: []PointsByScale{{ScaleCode: 60}, {ScaleCode: 45}, {ScaleCode: 10}},
+	}
+
+	var got []string
+	for _, p := range eq.ObservedPoints() {
+		got = append(got, p.Name+p.Scale)
+	}
+	// 震度の大きい順。座標の分からない観測点は含めない
+	want := []string{"輪島市6強", "金沢市5弱", "函館市1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ObservedPoints() = %v, want %v", got, want)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// 赤道上の経度 1 度は約 111.2km
+	if d := Distance(0, 135, 0, 136); math.Abs(d-111.19) > 0.01 {
+		t.Errorf("Distance() = %v, want 111.19", d)
+	}
+	if d := Distance(37.39, 136.899, 37.39, 136.899); d != 0 {
+		t.Errorf("Distance() = %v, want 0", d)
+	}
+}
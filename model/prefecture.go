@@ -0,0 +1,63 @@
+package model
+
+import "strings"
+
+// 都道府県（JIS X 0401 順）
+var Prefectures = []string{
+	"北海道", "青森県", "岩手県", "宮城県", "秋田県", "山形県", "福島県",
+	"茨城県", "栃木県", "群馬県", "埼玉県", "千葉県", "東京都", "神奈川県",
+	"新潟県", "富山県", "石川県", "福井県", "山梨県", "長野県", "岐阜県",
+	"静岡県", "愛知県", "三重県", "滋賀県", "京都府", "大阪府", "兵庫県",
+	"奈良県", "和歌山県", "鳥取県", "島根県", "岡山県", "広島県", "山口県",
+	"徳島県", "香川県", "愛媛県", "高知県", "福岡県", "佐賀県", "長崎県",
+	"熊本県", "大分県", "宮崎県", "鹿児島県", "沖縄県",
+}
+
+// 「石川」のような略称を「石川県」にする。該当しなければそのまま返す
+func NormalizePref(name string) string {
+	for _, p := range Prefectures {
+		if p == name {
+			return p
+		}
+	}
+	for _, p := range Prefectures {
+		if len(p) > len(name) && p[:len(name)] == name && len([]rune(p))-len([]rune(name)) == 1 {
+			return p
+		}
+	}
+	return name
+}
+
+// 都道府県名で始まらない津波予報区と、その沿岸の都道府県
+var tsunamiAreaPrefs = map[string][]string{
+	"オホーツク海沿岸":   {"北海道"},
+	"陸奥湾":        {"青森県"},
+	"東京湾内湾":      {"千葉県", "東京都", "神奈川県"},
+	"相模湾・三浦半島":   {"神奈川県"},
+	"伊豆諸島":       {"東京都"},
+	"小笠原諸島":      {"東京都"},
+	"伊勢・三河湾":     {"愛知県", "三重県"},
+	"淡路島南部":      {"兵庫県"},
+	"隠岐":         {"島根県"},
+	"佐渡":         {"新潟県"},
+	"有明・八代海":     {"福岡県", "佐賀県", "長崎県", "熊本県"},
+	"壱岐・対馬":      {"長崎県"},
+	"種子島・屋久島地方":  {"鹿児島県"},
+	"奄美群島・トカラ列島": {"鹿児島県"},
+	"沖縄本島地方":     {"沖縄県"},
+	"大東島地方":      {"沖縄県"},
+	"宮古島・八重山地方":  {"沖縄県"},
+}
+
+// 津波予報区の沿岸の都道府県
+func TsunamiAreaPrefs(name string) []string {
+	if prefs, ok := tsunamiAreaPrefs[name]; ok {
+		return prefs
+	}
+	for _, p := range Prefectures {
+		if strings.HasPrefix(name, p) {
+			return []string{p}
+		}
+	}
+	return nil
+}
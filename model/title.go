@@ -0,0 +1,67 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// フィードなどで使う 1 行の見出し
+func (e *Earthquake) Title() string {
+	h := e.HypocenterDetail
+	switch e.IssueType {
+	case "ScalePrompt":
+		return fmt.Sprintf("震度速報 最大震度%s", e.MaxScale)
+	case "Destination":
+		return fmt.Sprintf("震源情報 %s", e.hypocenterTitle())
+	case "Foreign":
+		if e.IsEruption {
+			return fmt.Sprintf("海外 大規模噴火 %s", h.Name)
+		}
+		return fmt.Sprintf("遠地地震 %s", e.hypocenterTitle())
+	}
+	return fmt.Sprintf("震度%s %s", e.MaxScale, e.hypocenterTitle())
+}
+
+func (e *Earthquake) hypocenterTitle() string {
+	h := e.HypocenterDetail
+	if h.Name == "" {
+		return "震源不明"
+	}
+	if h.Magnitude < 0 {
+		return h.Name
+	}
+	return fmt.Sprintf("%s M%.1f", h.Name, h.Magnitude)
+}
+
+func (t *Tsunami) Title() string {
+	if t.Cancelled {
+		return "津波予報 解除"
+	}
+	switch t.MaxGrade {
+	case "MajorWarning":
+		return "大津波警報"
+	case "Warning":
+		return "津波警報"
+	case "Watch":
+		return "津波注意報"
+	}
+	return "津波予報"
+}
+
+func (e *EEW) Title() string {
+	if e.Cancelled {
+		return "緊急地震速報（警報） 取消"
+	}
+	title := "緊急地震速報（警報）"
+	if e.Hypocenter != "" {
+		title += " " + e.Hypocenter
+	}
+	if len(e.Areas) > 0 {
+		title += " " + strings.Join(e.Areas, "・")
+	}
+	return title
+}
+
+func (u *Userquake) Title() string {
+	return "「揺れた！」 " + u.StartTime
+}
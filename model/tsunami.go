@@ -8,26 +8,31 @@ import (
 )
 
 type Tsunami struct {
-	Code        int
-	ObjectID    string
-	Time        string
-	IssueTime   string
-	ShortTime   string
-	Cancelled   bool
-	MaxGrade    string
-	AreaByGrade []AreaByGrade
+	Code        int           `json:"code"`
+	ObjectID    string        `json:"id"`
+	Time        string        `json:"time"`
+	IssueTime   string        `json:"issueTime"`
+	IssuedAt    *time.Time    `json:"issuedAt,omitempty"`
+	ShortTime   string        `json:"shortTime"`
+	Cancelled   bool          `json:"cancelled"`
+	MaxGrade    string        `json:"maxGrade"`
+	AreaByGrade []AreaByGrade `json:"areaByGrade"`
 }
 
 type AreaByGrade struct {
-	Grade string
-	Areas []ForecastArea
+	Grade string         `json:"grade"`
+	Areas []ForecastArea `json:"areas"`
 }
 
 type ForecastArea struct {
-	Name        string
-	Immediate   bool
-	ArrivalTime string
-	MaxHeight   string
+	Name           string     `json:"name"`
+	Grade          string     `json:"grade"`
+	Immediate      bool       `json:"immediate"`
+	ArrivalTime    string     `json:"arrivalTime"`
+	ArrivalAt      *time.Time `json:"arrivalAt,omitempty"`
+	Condition      string     `json:"condition,omitempty"`
+	MaxHeight      string     `json:"maxHeight"`
+	MaxHeightValue float64    `json:"maxHeightValue"`
 }
 
 type TsunamiRecord struct {
@@ -73,6 +78,7 @@ func ToTsunami(data primitive.M) (*Tsunami, error) {
 		ObjectID:    t.ID.Hex(),
 		Time:        formatS(t.Time),
 		IssueTime:   formatS(t.Issue.Time),
+		IssuedAt:    ParseTime(t.Issue.Time),
 		ShortTime:   formatShort(t.Issue.Time),
 		Cancelled:   t.Cancelled,
 		MaxGrade:    maxGrade,
@@ -91,10 +97,14 @@ func toAreaByGrade(areas []Area) ([]AreaByGrade, string) {
 	}
 	for _, area := range areas {
 		grades[area.Grade] = append(grades[area.Grade], ForecastArea{
-			Name:        area.Name,
-			Immediate:   area.Immediate,
-			ArrivalTime: formatArrivalTime(area.FirstHeight),
-			MaxHeight:   formatMaxHeight(area.MaxHeight),
+			Name:           area.Name,
+			Grade:          area.Grade,
+			Immediate:      area.Immediate,
+			ArrivalTime:    formatArrivalTime(area.FirstHeight),
+			ArrivalAt:      ParseTime(area.FirstHeight.ArrivalTime),
+			Condition:      area.FirstHeight.Condition,
+			MaxHeight:      formatMaxHeight(area.MaxHeight),
+			MaxHeightValue: area.MaxHeight.Value,
 		})
 	}
 
@@ -147,3 +157,132 @@ func formatM(t string) string {
 	}
 	return s.Format("15時04分")
 }
+
+// 予報区ごとの前回の情報からの変化
+type TsunamiAreaChange struct {
+	Name            string `json:"name"`
+	Kind            string `json:"kind"` // new（発表）, up（引き上げ）, down（引き下げ）, lifted（解除）, update（到達時刻・高さ）, 変化なしは空
+	Grade           string `json:"grade,omitempty"`
+	PrevGrade       string `json:"prevGrade,omitempty"`
+	ArrivalTime     string `json:"arrivalTime,omitempty"`
+	PrevArrivalTime string `json:"prevArrivalTime,omitempty"`
+	MaxHeight       string `json:"maxHeight,omitempty"`
+	PrevMaxHeight   string `json:"prevMaxHeight,omitempty"`
+}
+
+func (c TsunamiAreaChange) ArrivalChanged() bool {
+	return c.Kind != "new" && c.Kind != "lifted" && c.ArrivalTime != c.PrevArrivalTime
+}
+
+func (c TsunamiAreaChange) MaxHeightChanged() bool {
+	return c.Kind != "new" && c.Kind != "lifted" && c.MaxHeight != c.PrevMaxHeight
+}
+
+func (c TsunamiAreaChange) KindName() string {
+	switch c.Kind {
+	case "new":
+		return "発表"
+	case "up":
+		return "引き上げ"
+	case "down":
+		return "引き下げ"
+	case "lifted":
+		return "解除"
+	case "update":
+		return "更新"
+	}
+	return ""
+}
+
+// 予報の種類の名前
+func GradeName(grade string) string {
+	switch grade {
+	case "MajorWarning":
+		return "大津波警報"
+	case "Warning":
+		return "津波警報"
+	case "Watch":
+		return "津波注意報"
+	case "":
+		return ""
+	}
+	return "予報種類不明"
+}
+
+// 予報の重さ（不明は 0）
+func GradeRank(grade string) int {
+	switch grade {
+	case "MajorWarning":
+		return 3
+	case "Warning":
+		return 2
+	case "Watch":
+		return 1
+	}
+	return 0
+}
+
+// prev から cur への予報区ごとの変化。prev が nil なら全て発表とする。
+// cur の予報区（予報の重い順）の後に、解除された予報区を prev の順に並べる
+func DiffTsunami(prev, cur *Tsunami) []TsunamiAreaChange {
+	prevAreas := make(map[string]ForecastArea)
+	var prevNames []string
+	if prev != nil && !prev.Cancelled {
+		for _, g := range prev.AreaByGrade {
+			for _, a := range g.Areas {
+				prevAreas[a.Name] = a
+				prevNames = append(prevNames, a.Name)
+			}
+		}
+	}
+
+	var changes []TsunamiAreaChange
+	seen := make(map[string]bool)
+	if !cur.Cancelled {
+		for _, g := range cur.AreaByGrade {
+			for _, a := range g.Areas {
+				seen[a.Name] = true
+				c := TsunamiAreaChange{
+					Name:        a.Name,
+					Kind:        "new",
+					Grade:       a.Grade,
+					ArrivalTime: a.ArrivalTime,
+					MaxHeight:   a.MaxHeight,
+				}
+
+				if p, ok := prevAreas[a.Name]; ok {
+					c.PrevGrade = p.Grade
+					c.PrevArrivalTime = p.ArrivalTime
+					c.PrevMaxHeight = p.MaxHeight
+					switch {
+					case GradeRank(a.Grade) > GradeRank(p.Grade):
+						c.Kind = "up"
+					case GradeRank(a.Grade) < GradeRank(p.Grade):
+						c.Kind = "down"
+					case c.ArrivalTime != c.PrevArrivalTime || c.MaxHeight != c.PrevMaxHeight:
+						c.Kind = "update"
+					default:
+						c.Kind = ""
+					}
+				}
+				changes = append(changes, c)
+			}
+		}
+	}
+
+	for _, name := range prevNames {
+		if seen[name] {
+			continue
+		}
+		p := prevAreas[name]
+		changes = append(changes, TsunamiAreaChange{
+			Name:            name,
+			Kind:            "lifted",
+			PrevGrade:       p.Grade,
+			PrevArrivalTime: p.ArrivalTime,
+			PrevMaxHeight:   p.MaxHeight,
+		})
+	}
+
+	return changes
+}
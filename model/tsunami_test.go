@@ -0,0 +1,79 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func tsunamiOf(cancelled bool, areas ...ForecastArea) *Tsunami {
+	t := &Tsunami{Cancelled: cancelled}
+	for _, a := range areas {
+		t.AreaByGrade = append(t.AreaByGrade, AreaByGrade{Grade: a.Grade, Areas: []ForecastArea{a}})
+	}
+	return t
+}
+
+func TestDiffTsunami(t *testing.T) {
+	noto := ForecastArea{Name: "石川県能登", Grade: "Warning", ArrivalTime: "16:12", MaxHeight: "3m"}
+	kaga := ForecastArea{Name: "石川県加賀", Grade: "Watch", ArrivalTime: "16:20", MaxHeight: "1m"}
+
+	tests := []struct {
+		name      string
+		prev, cur *Tsunami
+		want      []TsunamiAreaChange
+	}{
+		{
+			name: "first",
+			cur:  tsunamiOf(false, noto),
+			want: []TsunamiAreaChange{{Name: "石川県能登", Kind: "new", Grade: "Warning", ArrivalTime: "16:12", MaxHeight: "3m"}},
+		},
+		{
+			name: "unchanged",
+			prev: tsunamiOf(false, noto),
+			cur:  tsunamiOf(false, noto),
+			want: []TsunamiAreaChange{{Name: "石川県能登", Grade: "Warning", PrevGrade: "Warning", ArrivalTime: "16:12", PrevArrivalTime: "16:12", MaxHeight: "3m", PrevMaxHeight: "3m"}},
+		},
+		{
+			name: "upgraded",
+			prev: tsunamiOf(false, kaga),
+			cur:  tsunamiOf(false, ForecastArea{Name: "石川県加賀", Grade: "MajorWarning", ArrivalTime: "16:20", MaxHeight: "5m"}),
+			want: []TsunamiAreaChange{{Name: "石川県加賀", Kind: "up", Grade: "MajorWarning", PrevGrade: "Watch", ArrivalTime: "16:20", PrevArrivalTime: "16:20", MaxHeight: "5m", PrevMaxHeight: "1m"}},
+		},
+		{
+			name: "downgraded and lifted",
+			prev: tsunamiOf(false, noto, kaga),
+			cur:  tsunamiOf(false, ForecastArea{Name: "石川県能登", Grade: "Watch", ArrivalTime: "16:12", MaxHeight: "1m"}),
+			want: []TsunamiAreaChange{
+				{Name: "石川県能登", Kind: "down", Grade: "Watch", PrevGrade: "Warning", ArrivalTime: "16:12", PrevArrivalTime: "16:12", MaxHeight: "1m", PrevMaxHeight: "3m"},
+				{Name: "石川県加賀", Kind: "lifted", PrevGrade: "Watch", PrevArrivalTime: "16:20", PrevMaxHeight: "1m"},
+			},
+		},
+		{
+			name: "arrival updated",
+			prev: tsunamiOf(false, kaga),
+			cur:  tsunamiOf(false, ForecastArea{Name: "石川県加賀", Grade: "Watch", ArrivalTime: "16:30", MaxHeight: "1m"}),
+			want: []TsunamiAreaChange{{Name: "石川県加賀", Kind: "update", Grade: "Watch", PrevGrade: "Watch", ArrivalTime: "16:30", PrevArrivalTime: "16:20", MaxHeight: "1m", PrevMaxHeight: "1m"}},
+		},
+		{
+			name: "cancelled",
+			prev: tsunamiOf(false, kaga),
+			cur:  tsunamiOf(true),
+			want: []TsunamiAreaChange{{Name: "石川県加賀", Kind: "lifted", PrevGrade: "Watch", PrevArrivalTime: "16:20", PrevMaxHeight: "1m"}},
+		},
+		{
+			name: "after cancellation",
+			prev: tsunamiOf(true, kaga),
+			cur:  tsunamiOf(false, kaga),
+			want: []TsunamiAreaChange{{Name: "石川県加賀", Kind: "new", Grade: "Watch", ArrivalTime: "16:20", MaxHeight: "1m"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffTsunami(tt.prev, tt.cur)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffTsunami() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"sort"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -9,17 +10,41 @@ import (
 )
 
 type Userquake struct {
-	Code             int
-	ObjectID         string
-	StartTime        string
-	ShortTime        string
-	EndTime          string
-	AreaByConfidence []AreaByConfidence
+	Code             int                `json:"code"`
+	ObjectID         string             `json:"id"`
+	StartTime        string             `json:"startTime"`
+	StartedAt        *time.Time         `json:"startedAt,omitempty"`
+	ShortTime        string             `json:"shortTime"`
+	EndTime          string             `json:"endTime"`
+	UpdatedAt        *time.Time         `json:"updatedAt,omitempty"`
+	AreaByConfidence []AreaByConfidence `json:"areaByConfidence"`
+	Areas            []UserquakeArea    `json:"areas"`
+	Checked          bool               `json:"-"`               // 地震情報との対応を調べたか
+	Match            *UserquakeMatch    `json:"match,omitempty"` // 対応する地震情報
+}
+
+type UserquakeMatch struct {
+	EarthquakeID string  `bson:"earthquake_id" json:"earthquakeId"`
+	Title        string  `bson:"title" json:"title"`
+	Score        float64 `bson:"score" json:"score"` // 0〜1
+}
+
+// 一致度（%）
+func (m UserquakeMatch) Percent() int {
+	return int(m.Score*100 + 0.5)
 }
 
 type AreaByConfidence struct {
-	Confidence string
-	Areas      []string
+	Confidence string   `json:"confidence"`
+	Areas      []string `json:"areas"`
+}
+
+// 地域ごとの信頼度（正規化後、信頼度の高い順）
+type UserquakeArea struct {
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+	Label      string  `json:"label"`
 }
 
 type UserquakeRecord struct {
@@ -27,6 +52,15 @@ type UserquakeRecord struct {
 	StartedAt       string                    `bson:"started_at"`
 	UpdatedAt       string                    `bson:"updated_at"`
 	AreaConfidences map[string]AreaConfidence `bson:"area_confidences"`
+	// 地震情報との対応を調べた結果（event.Correlator が保存する）
+	Match *UserquakeMatchRecord `bson:"match"`
+}
+
+type UserquakeMatchRecord struct {
+	Found bool           `bson:"found"`
+	Match UserquakeMatch `bson:"result"`
+	// 後から地震情報が届いても変わらないか。false なら表示のたびに調べ直す
+	Settled bool `bson:"settled"`
 }
 
 type AreaConfidence struct {
@@ -43,17 +77,29 @@ func ToUserquake(data primitive.M) (*Userquake, error) {
 	bytes, _ := bson.Marshal(data)
 	bson.Unmarshal(bytes, &uq)
 
+	abcs, areas := toAreaByConfidence(uq.AreaConfidences)
+
+	var match *UserquakeMatch
+	if uq.Match != nil && uq.Match.Found {
+		match = &uq.Match.Match
+	}
+
 	return &Userquake{
 		Code:             9611,
 		ObjectID:         uq.ID.Hex(),
 		StartTime:        formatS(uq.StartedAt),
+		StartedAt:        ParseTime(uq.StartedAt),
 		ShortTime:        formatShort(uq.StartedAt),
 		EndTime:          formatTS(uq.UpdatedAt),
-		AreaByConfidence: toAreaByConfidence(uq.AreaConfidences),
+		UpdatedAt:        ParseTime(uq.UpdatedAt),
+		AreaByConfidence: abcs,
+		Areas:            areas,
+		Checked:          uq.Match != nil,
+		Match:            match,
 	}, nil
 }
 
-func toAreaByConfidence(ac map[string]AreaConfidence) []AreaByConfidence {
+func toAreaByConfidence(ac map[string]AreaConfidence) ([]AreaByConfidence, []UserquakeArea) {
 	// 正規化
 	max := 0.125
 	for _, areaConfidence := range ac {
@@ -79,6 +125,16 @@ func toAreaByConfidence(ac map[string]AreaConfidence) []AreaByConfidence {
 	}
 	sort.SliceStable(areas, func(i, j int) bool { return areas[i].Confidence > areas[j].Confidence })
 
+	var uqAreas []UserquakeArea
+	for _, area := range areas {
+		uqAreas = append(uqAreas, UserquakeArea{
+			Code:       area.Area,
+			Name:       convertArea(area.Area),
+			Confidence: area.Confidence,
+			Label:      confidenceLabel(area.Confidence),
+		})
+	}
+
 	// マップ化
 	var abcs []AreaByConfidence
 	for _, area := range areas {
@@ -107,7 +163,7 @@ func toAreaByConfidence(ac map[string]AreaConfidence) []AreaByConfidence {
 		abcs[i].Areas = areas
 	}
 
-	return abcs
+	return abcs, uqAreas
 }
 
 func confidenceLabel(confidence float64) string {
@@ -283,6 +339,35 @@ var areaMap = map[string]string{
 	"710": "沖縄大東島",
 }
 
+// 名前が都道府県名で始まらない地域
+var areaPrefs = map[string]string{
+	"255": "東京都",
+	"260": "東京都",
+	"265": "東京都",
+	"680": "鹿児島県",
+}
+
+// 地域の都道府県。分からなければ空
+func AreaPref(code string) string {
+	if pref, ok := areaPrefs[code]; ok {
+		return pref
+	}
+	name, ok := areaMap[code]
+	if !ok {
+		return ""
+	}
+	for _, pref := range Prefectures {
+		short := pref
+		if pref != "北海道" {
+			short = string([]rune(pref)[:len([]rune(pref))-1])
+		}
+		if strings.HasPrefix(name, short) {
+			return pref
+		}
+	}
+	return ""
+}
+
 func convertArea(code string) string {
 	if area, ok := areaMap[code]; ok {
 		return area
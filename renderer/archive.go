@@ -0,0 +1,29 @@
+package renderer
+
+type ArchiveDay struct {
+	Title string
+	Up    string
+	Prev  string
+	Next  string
+	Items []interface{}
+}
+
+type ArchiveSummary struct {
+	Title string
+	Up    string
+	Prev  string
+	Next  string
+	Rows  []ArchiveRow
+}
+
+type ArchiveRow struct {
+	Label        string
+	Link         string
+	Total        int
+	Earthquakes  int
+	Tsunamis     int
+	EEWs         int
+	Userquakes   int
+	MaxScale     string
+	MaxScaleCode int
+}
@@ -0,0 +1,69 @@
+package renderer
+
+import (
+	"github.com/p2pquake/web-client/event"
+	"github.com/p2pquake/web-client/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EventPage struct {
+	Title   string
+	Related []Related
+	Items   []interface{}
+}
+
+// 同じ地震の情報へのリンク
+type Related struct {
+	ID      string
+	Title   string
+	Time    string
+	Current bool
+}
+
+func RenderEvent(e *event.Event) (string, error) {
+	page := EventPage{Related: ToRelated(e, "")}
+	for _, m := range e.Members {
+		page.Items = append(page.Items, m.Data)
+	}
+
+	// 最も新しい地震情報を見出しにする
+	for _, m := range e.Members {
+		if eq, ok := m.Data.(*model.Earthquake); ok {
+			page.Title = eq.Title()
+		}
+	}
+	if page.Title == "" && len(page.Related) > 0 {
+		page.Title = page.Related[0].Title
+	}
+
+	return Render("event.html", Root(1), page) // /event/{id}
+}
+
+// currentID は表示中の情報
+func ToRelated(e *event.Event, currentID string) []Related {
+	if e == nil {
+		return nil
+	}
+
+	var related []Related
+	for _, m := range e.Members {
+		r := Related{Time: relatedTime(m.Item)}
+		if id, ok := m.Item["_id"].(primitive.ObjectID); ok {
+			r.ID = id.Hex()
+		}
+		if t, ok := m.Data.(interface{ Title() string }); ok {
+			r.Title = t.Title()
+		}
+		r.Current = r.ID == currentID
+		related = append(related, r)
+	}
+	return related
+}
+
+func relatedTime(item bson.M) string {
+	if t := model.ParseTime(event.ItemTime(item)); t != nil {
+		return t.Format("01/02 15:04:05")
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package renderer
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type Feed struct {
+	Title   string
+	Link    string // サイトの URL
+	Self    string // フィード自身の URL
+	Updated time.Time
+	Entries []FeedEntry
+}
+
+type FeedEntry struct {
+	ID      string
+	Title   string
+	Link    string
+	Summary string
+	Updated time.Time
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+	Summary string   `xml:"summary"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+func RenderAtom(f Feed) ([]byte, error) {
+	feed := atomFeed{
+		Title:   f.Title,
+		ID:      f.Self,
+		Updated: f.Updated.Format(time.RFC3339),
+		Links:   []atomLink{{Href: f.Link}, {Href: f.Self, Rel: "self"}},
+		Author:  atomAuthor{Name: "P2P地震情報"},
+	}
+	for _, e := range f.Entries {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   e.Title,
+			ID:      e.ID,
+			Updated: e.Updated.Format(time.RFC3339),
+			Link:    atomLink{Href: e.Link},
+			Summary: e.Summary,
+		})
+	}
+	return marshalXML(feed)
+}
+
+func RenderRSS(f Feed) ([]byte, error) {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			Language:      "ja",
+			LastBuildDate: f.Updated.Format(time.RFC1123Z),
+		},
+	}
+	for _, e := range f.Entries {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			Description: e.Summary,
+			GUID:        rssGUID{Value: e.ID, IsPermaLink: true},
+			PubDate:     e.Updated.Format(time.RFC1123Z),
+		})
+	}
+	return marshalXML(feed)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
@@ -0,0 +1,101 @@
+package renderer
+
+import (
+	"encoding/json"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+func NewFeatureCollection(features []Feature) FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+func RenderGeoJSON(features []Feature) ([]byte, error) {
+	return json.Marshal(NewFeatureCollection(features))
+}
+
+// 震源と、座標の分かる震度観測点を地物にする
+func ToFeatures(data interface{}) []Feature {
+	var features []Feature
+	switch v := data.(type) {
+	case *model.Earthquake:
+		if f, ok := hypocenterFeature(v.HypocenterDetail); ok {
+			f.Properties["id"] = v.ObjectID
+			f.Properties["code"] = v.Code
+			f.Properties["issueType"] = v.IssueType
+			f.Properties["time"] = v.OccurredAt
+			f.Properties["maxScale"] = v.MaxScale
+			f.Properties["maxScaleCode"] = v.MaxScaleCode
+			features = append(features, f)
+		}
+		for _, p := range v.ObservedPoints() {
+			features = append(features, Feature{
+				Type:     "Feature",
+				Geometry: point(p.Latitude, p.Longitude),
+				Properties: map[string]interface{}{
+					"kind":      "point",
+					"id":        v.ObjectID,
+					"pref":      p.Pref,
+					"name":      p.Name,
+					"cityCode":  p.Code,
+					"scale":     p.Scale,
+					"scaleCode": p.ScaleCode,
+				},
+			})
+		}
+	case *model.EEW:
+		if f, ok := hypocenterFeature(v.HypocenterDetail); ok {
+			f.Properties["id"] = v.ObjectID
+			f.Properties["code"] = v.Code
+			f.Properties["time"] = v.OccurredAt
+			f.Properties["serial"] = v.Serial
+			features = append(features, f)
+		}
+	}
+	return features
+}
+
+func hypocenterFeature(h model.Hypocenter) (Feature, bool) {
+	if !validCoordinate(h.Latitude, h.Longitude) {
+		return Feature{}, false
+	}
+
+	return Feature{
+		Type:     "Feature",
+		Geometry: point(h.Latitude, h.Longitude),
+		Properties: map[string]interface{}{
+			"kind":      "hypocenter",
+			"name":      h.Name,
+			"depth":     h.Depth,
+			"magnitude": h.Magnitude,
+		},
+	}, true
+}
+
+func point(latitude, longitude float64) Geometry {
+	return Geometry{Type: "Point", Coordinates: []float64{longitude, latitude}}
+}
+
+// 不明な座標は -200 などになっている
+func validCoordinate(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
@@ -0,0 +1,113 @@
+package renderer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/p2pquake/web-client/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func earthquake(t *testing.T, latitude, longitude float64, points bson.A) interface{} {
+	t.Helper()
+	data, err := model.Convert(bson.M{
+		"_id": primitive.NewObjectID(), "code": 551, "time": "2026/10/01 10:00:00.000",
+		"issue": bson.M{"type": "DetailScale", "time": "2026/10/01 10:00:00"},
+		"earthquake": bson.M{
+			"time": "2026/10/01 09:58:00", "maxScale": 40,
+			"hypocenter": bson.M{"name": "浦河沖", "latitude": latitude, "longitude": longitude, "depth": 50, "magnitude": 6.1},
+		},
+		"points": points,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestToFeatures(t *testing.T) {
+	points := bson.A{
+		bson.M{"pref": "北海道", "addr": "函館市美原", "isArea": false, "scale": 30},
+		bson.M{"pref": "北海道", "addr": "釧路市黒金町", "isArea": false, "scale": 40},
+		bson.M{"pref": "北海道", "addr": "存在しない村役場", "isArea": false, "scale": 40},
+	}
+
+	tests := []struct {
+		name  string
+		data  interface{}
+		kinds []string
+	}{
+		{name: "earthquake", data: earthquake(t, 42.0, 142.6, points), kinds: []string{"hypocenter", "point", "point"}},
+		{name: "unknown hypocenter", data: earthquake(t, -200, -200, points), kinds: []string{"point", "point"}},
+		{name: "no points", data: earthquake(t, 42.0, 142.6, nil), kinds: []string{"hypocenter"}},
+		{name: "eew", data: &model.EEW{Code: 556, HypocenterDetail: model.Hypocenter{Latitude: 42.0, Longitude: 142.6}}, kinds: []string{"hypocenter"}},
+		{name: "eew without hypocenter", data: &model.EEW{Code: 556, HypocenterDetail: model.Hypocenter{Latitude: -200, Longitude: -200}}},
+		{name: "other", data: &model.Tsunami{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			features := ToFeatures(tt.data)
+			var kinds []string
+			for _, f := range features {
+				kinds = append(kinds, f.Properties["kind"].(string))
+			}
+			if len(kinds) != len(tt.kinds) {
+				t.Fatalf("kinds = %v, want %v", kinds, tt.kinds)
+			}
+			for i := range kinds {
+				if kinds[i] != tt.kinds[i] {
+					t.Errorf("kinds = %v, want %v", kinds, tt.kinds)
+				}
+			}
+		})
+	}
+
+	// 座標は経度・緯度の順。観測点は震度の大きい順
+	features := ToFeatures(earthquake(t, 42.0, 142.6, points))
+	if c := features[0].Geometry.Coordinates; c[0] != 142.6 || c[1] != 42.0 {
+		t.Errorf("hypocenter coordinates = %v, want [142.6 42]", c)
+	}
+	if p := features[1].Properties; p["name"] != "釧路市" || p["scaleCode"] != 40 || p["cityCode"] != "01206" {
+		t.Errorf("first point = %v", p)
+	}
+	if p := features[2].Properties; p["name"] != "函館市" || p["scaleCode"] != 30 {
+		t.Errorf("second point = %v", p)
+	}
+}
+
+func TestRenderGeoJSON(t *testing.T) {
+	b, err := RenderGeoJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"FeatureCollection","features":[]}` {
+		t.Errorf("RenderGeoJSON(nil) = %s", b)
+	}
+
+	b, err = RenderGeoJSON(ToFeatures(earthquake(t, 42.0, 142.6, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Type     string `json:"type"`
+			Geometry struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(b, &fc); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 1 || fc.Features[0].Type != "Feature" || fc.Features[0].Geometry.Type != "Point" {
+		t.Errorf("RenderGeoJSON() = %s", b)
+	}
+	if p := fc.Features[0].Properties; p["magnitude"] != 6.1 || p["depth"] != 50.0 || p["time"] != "2026-10-01T09:58:00+09:00" {
+		t.Errorf("properties = %v", p)
+	}
+}
@@ -3,17 +3,76 @@ package renderer
 import (
 	"github.com/p2pquake/web-client/model"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func RenderIndex(ms []bson.M) (string, error) {
+type Index struct {
+	Items []Card
+	Older string // 古い情報へのリンク
+	Newer string // 新しい情報へのリンク（空なら最新）
+}
+
+// 一覧に並べる 1 件。Key と Time は自動更新時の差し替え・並び替えに使う
+type Card struct {
+	Key  string
+	Time string
+	Data interface{}
+}
+
+func (i Index) Live() bool {
+	return i.Newer == ""
+}
+
+func RenderIndex(ms []bson.M, older, newer string) (string, error) {
+	cards := make([]Card, len(ms))
+	for i, m := range ms {
+		var err error
+		cards[i], err = ToCard(m)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return Render("index.html", Root(0), Index{Items: cards, Older: older, Newer: newer})
+}
+
+// 一覧の 1 件分の HTML
+func RenderFragment(m bson.M) (string, error) {
+	card, err := ToCard(m)
+	if err != nil {
+		return "", err
+	}
+
+	return render("", "card.html", Root(0), card)
+}
+
+func ToCard(m bson.M) (Card, error) {
+	data, err := model.Convert(m)
+	if err != nil {
+		return Card{}, err
+	}
+
+	card := Card{Data: data}
+	card.Time, _ = m["time"].(string)
+	if id, ok := m["_id"].(primitive.ObjectID); ok {
+		card.Key = id.Hex()
+	}
+	if startedAt, ok := m["started_at"].(string); ok {
+		// 地震感知情報は started_at ごとに 1 件
+		card.Key = "userquake-" + startedAt
+		card.Time = startedAt
+	}
+	return card, nil
+}
+
+func ConvertAll(ms []bson.M) ([]interface{}, error) {
 	data := make([]interface{}, len(ms))
 	var err error
 	for i, m := range ms {
 		data[i], err = model.Convert(m)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
-
-	return Render("index.html", data)
+	return data, nil
 }
@@ -1,15 +1,62 @@
 package renderer
 
 import (
+	"github.com/p2pquake/web-client/event"
 	"github.com/p2pquake/web-client/model"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-func RenderItem(m bson.M) (string, error) {
-	data, err := model.Convert(m)
+type Detail struct {
+	Data      interface{}
+	ObjectID  string
+	Bulletins []*model.Bulletin
+	Related   []Related // 同じ地震の情報（自身を含む）
+	EEWResult *event.EEWResult
+	Revision  *event.Revision // 前回の地震情報からの変化
+}
+
+func RenderItem(m bson.M, bulletins []bson.M, e *event.Event) (string, error) {
+	detail, err := toDetail(m, bulletins)
 	if err != nil {
 		return "", err
 	}
+	detail.Related = ToRelated(e, detail.ObjectID)
+	if _, ok := detail.Data.(*model.EEW); ok && e != nil {
+		detail.EEWResult = event.VerifyEEW(e)
+	}
+	if eq, ok := detail.Data.(*model.Earthquake); ok && e != nil {
+		detail.Revision = event.Revise(e, eq)
+	}
 
-	return Render("item.html", data)
+	return Render("detail.html", Root(0), detail)
+}
+
+// 気象庁の電文
+func RenderBulletins(m bson.M, bulletins []bson.M) (string, error) {
+	detail, err := toDetail(m, bulletins)
+	if err != nil {
+		return "", err
+	}
+
+	return Render("bulletin.html", Root(1), detail) // /source/{id}
+}
+
+func toDetail(m bson.M, bulletins []bson.M) (Detail, error) {
+	data, err := model.Convert(m)
+	if err != nil {
+		return Detail{}, err
+	}
+
+	detail := Detail{Data: data}
+	if id, ok := m["_id"].(interface{ Hex() string }); ok {
+		detail.ObjectID = id.Hex()
+	}
+	for _, b := range bulletins {
+		bulletin, err := model.ToBulletin(b)
+		if err != nil {
+			return Detail{}, err
+		}
+		detail.Bulletins = append(detail.Bulletins, bulletin)
+	}
+	return detail, nil
 }
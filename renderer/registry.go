@@ -0,0 +1,181 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// 起動時に全テンプレートを読み込んでおき、ページごとに複製して使う
+type Registry struct {
+	dir   string
+	funcs func(root string) template.FuncMap // root はページからサイトのルートへの相対パス
+
+	mu       sync.RWMutex
+	pages    map[string]*template.Template // ページ（content として読み込んだもの）。実行せずに複製元として残す
+	fragment *template.Template            // ページを伴わない部品用
+	rooted   map[string]*template.Template // root ごとに複製したもの。キーは root と templateFile
+	modTime  time.Time
+}
+
+func NewRegistry(dir string, funcs func(root string) template.FuncMap) (*Registry, error) {
+	r := &Registry{dir: dir, funcs: funcs}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// テンプレートを読み込み直す。失敗した場合はそれまでのものを使い続ける
+func (r *Registry) Reload() error {
+	files, err := filepath.Glob(filepath.Join(r.dir, "*.html"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no templates in %s", r.dir)
+	}
+
+	base, err := template.New("").Funcs(r.funcs("./")).ParseFiles(files...)
+	if err != nil {
+		return err
+	}
+
+	pages := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		page, err := base.Clone()
+		if err != nil {
+			return err
+		}
+		if _, err := page.New("content").Parse(string(b)); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		pages[filepath.Base(file)] = page
+	}
+
+	fragment, err := base.Clone()
+	if err != nil {
+		return err
+	}
+
+	modTime, _ := latestModTime(files)
+
+	r.mu.Lock()
+	r.pages = pages
+	r.fragment = fragment
+	r.rooted = make(map[string]*template.Template)
+	r.modTime = modTime
+	r.mu.Unlock()
+	return nil
+}
+
+// templateFile を content として name のテンプレートを実行する。templateFile が空なら部品として実行する。
+// リンクは root（ページからサイトのルートへの相対パス）から組み立てる
+func (r *Registry) Execute(templateFile, name, root string, data interface{}) (string, error) {
+	t, err := r.lookup(templateFile, root)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	if err := t.ExecuteTemplate(&b, name, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// 実行したテンプレートは複製できないため、root ごとに一度だけ複製しておく
+func (r *Registry) lookup(templateFile, root string) (*template.Template, error) {
+	key := root + "\x00" + templateFile
+
+	r.mu.RLock()
+	t, ok := r.rooted[key]
+	r.mu.RUnlock()
+	if ok {
+		return t, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.rooted[key]; ok {
+		return t, nil
+	}
+
+	src := r.fragment
+	if templateFile != "" {
+		src = r.pages[templateFile]
+	}
+	if src == nil {
+		return nil, fmt.Errorf("template not found: %s", templateFile)
+	}
+
+	t, err := src.Clone()
+	if err != nil {
+		return nil, err
+	}
+	t.Funcs(r.funcs(root))
+	r.rooted[key] = t
+	return t, nil
+}
+
+// 開発用。ctx が終わるまで interval ごとにテンプレートの更新を確認し、変更があれば読み込み直す
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		files, _ := filepath.Glob(filepath.Join(r.dir, "*.html"))
+		modTime, err := latestModTime(files)
+		if err != nil {
+			continue
+		}
+
+		r.mu.RLock()
+		changed := !modTime.Equal(r.modTime) || len(files) != len(r.pages)
+		r.mu.RUnlock()
+		if !changed {
+			continue
+		}
+
+		if err := r.Reload(); err != nil {
+			log.Printf("Template reload error: %v\n", err)
+			// 同じ状態で何度も失敗しないよう、確認済みの時刻だけ進める
+			r.mu.Lock()
+			r.modTime = modTime
+			r.mu.Unlock()
+			continue
+		}
+		log.Printf("Templates reloaded\n")
+	}
+}
+
+func latestModTime(files []string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,115 @@
+package renderer
+
+import (
+	"context"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFuncs(root string) template.FuncMap {
+	return template.FuncMap{"root": func() string { return root }}
+}
+
+func writeTemplate(t *testing.T, dir, name, text string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestRegistry(t *testing.T) (*Registry, string) {
+	t.Helper()
+	dir := t.TempDir()
+	writeTemplate(t, dir, "layout.html", `<a href="{{ root }}x">{{ template "content" . }}</a>`)
+	writeTemplate(t, dir, "page.html", `page {{ . }}`)
+	writeTemplate(t, dir, "part.html", `part {{ . }}`)
+
+	r, err := NewRegistry(dir, testFuncs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, dir
+}
+
+func execute(t *testing.T, r *Registry, templateFile, name, root string, data interface{}) string {
+	t.Helper()
+	s, err := r.Execute(templateFile, name, root, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestRegistryExecute(t *testing.T) {
+	r, _ := newTestRegistry(t)
+
+	tests := []struct {
+		templateFile, name, root string
+		want                     string
+	}{
+		{"page.html", "layout.html", "./", `<a href="./x">page 1</a>`},
+		{"page.html", "layout.html", "../../", `<a href="../../x">page 1</a>`},
+		{"part.html", "layout.html", "./", `<a href="./x">part 1</a>`},
+		{"", "part.html", "./", `part 1`},
+	}
+	for _, tt := range tests {
+		if got := execute(t, r, tt.templateFile, tt.name, tt.root, 1); got != tt.want {
+			t.Errorf("Execute(%q, %q, %q) = %q, want %q", tt.templateFile, tt.name, tt.root, got, tt.want)
+		}
+	}
+
+	if _, err := r.Execute("missing.html", "layout.html", "./", 1); err == nil {
+		t.Error("Execute() with a missing template returns no error")
+	}
+}
+
+func TestRegistryReload(t *testing.T) {
+	r, dir := newTestRegistry(t)
+	execute(t, r, "page.html", "layout.html", "./", 1)
+
+	writeTemplate(t, dir, "page.html", `new {{ . }}`)
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := execute(t, r, "page.html", "layout.html", "./", 1); got != `<a href="./x">new 1</a>` {
+		t.Errorf("after Reload = %q", got)
+	}
+
+	// 構文エラーなら前のものを使い続ける
+	writeTemplate(t, dir, "page.html", `broken {{ .`)
+	if err := r.Reload(); err == nil || !strings.Contains(err.Error(), "page.html") {
+		t.Errorf("Reload() error = %v, want a page.html error", err)
+	}
+	if got := execute(t, r, "page.html", "layout.html", "./", 1); got != `<a href="./x">new 1</a>` {
+		t.Errorf("after a failed Reload = %q", got)
+	}
+}
+
+func TestRegistryWatch(t *testing.T) {
+	r, dir := newTestRegistry(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx, 10*time.Millisecond)
+
+	// 更新時刻の分解能が粗いファイルシステムでも変更と分かるようにする
+	writeTemplate(t, dir, "page.html", `watched {{ . }}`)
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(filepath.Join(dir, "page.html"), later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if got := execute(t, r, "page.html", "layout.html", "./", 1); got == `<a href="./x">watched 1</a>` {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("templates were not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,35 +1,88 @@
 package renderer
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"html/template"
-	"io"
 	"os"
+	"strings"
 	"time"
+
+	"github.com/p2pquake/web-client/model"
 )
 
-func Render(templateFile string, data interface{}) (string, error) {
-	f, err := os.ReadFile("./template/" + templateFile)
-	if err != nil {
-		return "", err
+var registry *Registry
+
+// root はページからサイトのルートへの相対パス。サブパスに置いても動くよう、リンクはこれから組み立てる
+func funcs(root string) template.FuncMap {
+	return template.FuncMap{
+		"date": func() string { return time.Now().In(model.JST).Format("01/02 15:04:05") },
+		"gtag": func() string { return os.Getenv("GTM_CONTAINER_ID") },
+		"root": func() string { return root },
+		"hypocenterMap": func(id string) string {
+			if localMap() {
+				return root + "map/hypocenter/" + id + ".svg"
+			}
+			return "https://cdn.p2pquake.net/app/web/hypocenter?id=" + id + "&suffix=_trim_big"
+		},
+		"tsunamiMap": func(id string) string {
+			if localMap() {
+				return root + "map/tsunami/" + id + ".svg"
+			}
+			return "https://cdn.p2pquake.net/app/web/tsunami?id=" + id + "&suffix=_trim"
+		},
+		"userquakeMap": func(id string) string {
+			if localMap() {
+				return root + "map/userquake/" + id + ".svg"
+			}
+			return "https://cdn.p2pquake.net/app/web/userquake?id=" + id + "&suffix=_trim"
+		},
+		"localMap":  localMap,
+		"feeds":     func() bool { return os.Getenv("BASE_URL") != "" },
+		"gradeName": model.GradeName,
 	}
+}
 
-	t, err := template.New("content").Funcs(template.FuncMap{"date": func() string { return time.Now().Format("01/02 15:04:05") }, "gtag": func() string { return os.Getenv("GTM_CONTAINER_ID") }}).Parse(string(f))
-	if err != nil {
-		return "", err
+// パスの深さ（/ で区切った段数 - 1）のページからサイトのルートへの相対パス
+func Root(depth int) string {
+	if depth <= 0 {
+		return "./"
 	}
+	return strings.Repeat("../", depth)
+}
+
+// MAP_RENDERER=local なら外部の画像の代わりに自前で描いた地図を使う
+func localMap() bool {
+	return os.Getenv("MAP_RENDERER") == "local"
+}
 
-	t, err = t.ParseGlob("./template/*.html")
+// dir のテンプレートをすべて読み込む。構文エラーがあればエラーを返す
+func Load(dir string) error {
+	r, err := NewRegistry(dir, funcs)
 	if err != nil {
-		return "", err
+		return err
 	}
+	registry = r
+	return nil
+}
 
-	var b bytes.Buffer
-	w := io.Writer(&b)
-	err = t.ExecuteTemplate(w, "layout.html", data)
-	if err != nil {
-		return "", err
+// 開発用。テンプレートの変更を監視して読み込み直す
+func Watch(ctx context.Context) {
+	if registry != nil {
+		registry.Watch(ctx, time.Second)
 	}
+}
 
-	return b.String(), nil
+// root は Root で求めたページからサイトのルートへの相対パス
+func Render(templateFile, root string, data interface{}) (string, error) {
+	return render(templateFile, "layout.html", root, data)
+}
+
+// templateFile を content として読み込み、name のテンプレートを実行する。
+// templateFile が空ならページを伴わない部品として実行する
+func render(templateFile, name, root string, data interface{}) (string, error) {
+	if registry == nil {
+		return "", errors.New("templates are not loaded")
+	}
+	return registry.Execute(templateFile, name, root, data)
 }
@@ -0,0 +1,56 @@
+package renderer
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+type Search struct {
+	Query url.Values
+	Items []interface{}
+	Older string
+	Newer string
+}
+
+type Option struct {
+	Value string
+	Label string
+}
+
+func (s Search) Scales() []Option {
+	var options []Option
+	for _, code := range model.ScaleCodes {
+		options = append(options, Option{Value: strconv.Itoa(code), Label: model.ScaleName(code)})
+	}
+	return options
+}
+
+func (s Search) Prefectures() []string {
+	return model.Prefectures
+}
+
+func (s Search) IssueTypes() []Option {
+	return []Option{
+		{Value: "ScalePrompt", Label: "震度速報"},
+		{Value: "Destination", Label: "震源情報"},
+		{Value: "ScaleAndDestination", Label: "震度・震源情報"},
+		{Value: "DetailScale", Label: "地震情報（各地の震度）"},
+		{Value: "Foreign", Label: "遠地（海外）地震情報"},
+	}
+}
+
+// パラメータに value が含まれているか（選択状態の復元用）
+func (s Search) Selected(key, value string) bool {
+	for _, v := range s.Query[key] {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func RenderSearch(s Search) (string, error) {
+	return Render("search.html", Root(0), s)
+}
@@ -0,0 +1,41 @@
+package renderer
+
+import (
+	"html/template"
+	"net/url"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/stats"
+)
+
+type Stats struct {
+	Query      url.Values
+	Summary    *stats.Summary
+	ChartQuery template.URL // グラフの画像に渡す条件（エンコード済み）
+}
+
+func (s Stats) Intervals() []Option {
+	return []Option{
+		{Value: stats.Day, Label: "日ごと"},
+		{Value: stats.Week, Label: "週ごと"},
+		{Value: stats.Month, Label: "月ごと"},
+	}
+}
+
+func (s Stats) Prefectures() []string {
+	return model.Prefectures
+}
+
+// パラメータに value が含まれているか（選択状態の復元用）
+func (s Stats) Selected(key, value string) bool {
+	for _, v := range s.Query[key] {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func RenderStats(s Stats) (string, error) {
+	return Render("stats.html", Root(0), s)
+}
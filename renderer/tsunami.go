@@ -0,0 +1,45 @@
+package renderer
+
+import (
+	"github.com/p2pquake/web-client/event"
+	"github.com/p2pquake/web-client/model"
+)
+
+type TsunamiTimeline struct {
+	Title   string
+	Entries []TsunamiEntry // 新しい順
+}
+
+type TsunamiEntry struct {
+	ID      string
+	Tsunami *model.Tsunami
+	Changes []model.TsunamiAreaChange // 前の情報からの変化
+	Current bool
+}
+
+// 一連の津波情報の推移。currentID は表示の起点にした情報
+func RenderTsunamiTimeline(steps []event.TsunamiStep, currentID string) (string, error) {
+	var page TsunamiTimeline
+	var prev *model.Tsunami
+	for _, s := range steps {
+		page.Entries = append([]TsunamiEntry{{
+			ID:      s.Tsunami.ObjectID,
+			Tsunami: s.Tsunami,
+			Changes: model.DiffTsunami(prev, s.Tsunami),
+			Current: s.Tsunami.ObjectID == currentID,
+		}}, page.Entries...)
+		prev = s.Tsunami
+	}
+
+	// 最も重い予報を見出しにする
+	page.Title = "津波予報"
+	rank := 0
+	for _, s := range steps {
+		if r := model.GradeRank(s.Tsunami.MaxGrade); r > rank && !s.Tsunami.Cancelled {
+			rank = r
+			page.Title = s.Tsunami.Title()
+		}
+	}
+
+	return Render("tsunami_timeline.html", Root(1), page) // /tsunami/{id}
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/p2pquake/web-client/model"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// 過去の情報の一覧（アーカイブ）の期間ごとの件数
+type ArchiveCount struct {
+	Key      string `bson:"key"` // 受信時刻（地震感知情報は started_at）の先頭 keyLen 文字
+	Code     int    `bson:"code"`
+	Count    int    `bson:"count"`    // 地震感知情報は started_at ごとに 1 件
+	MaxScale int    `bson:"maxScale"` // 地震情報の最大震度の最大値
+}
+
+// 取得済みの一覧から数える（FindJmas / FindUserquakes の結果）
+func countArchive(items []bson.M, keyLen int) []ArchiveCount {
+	var counts []ArchiveCount
+	index := make(map[[2]interface{}]int)
+	for _, item := range items {
+		t := str(item, "time")
+		if len(t) < keyLen {
+			continue
+		}
+		k := [2]interface{}{t[:keyLen], model.ToInt(item["code"])}
+		i, ok := index[k]
+		if !ok {
+			i = len(counts)
+			index[k] = i
+			counts = append(counts, ArchiveCount{Key: t[:keyLen], Code: model.ToInt(item["code"])})
+		}
+		counts[i].Count++
+		if eq, ok := item["earthquake"].(bson.M); ok {
+			counts[i].MaxScale = max(counts[i].MaxScale, model.ToInt(eq["maxScale"]))
+		}
+	}
+	return counts
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func archiveFixtures() []bson.M {
+	return []bson.M{
+		{"_id": oid(1), "code": 551, "time": "2026/09/30 23:59:59.999", "earthquake": bson.M{"maxScale": 30}},
+		{"_id": oid(2), "code": 551, "time": "2026/10/01 10:00:00.000", "earthquake": bson.M{"maxScale": 45}},
+		{"_id": oid(3), "code": 551, "time": "2026/10/01 10:30:00.000", "earthquake": bson.M{"maxScale": 50}},
+		{"_id": oid(4), "code": 551, "time": "2026/10/02 10:00:00.000", "earthquake": bson.M{"maxScale": -1}},
+		{"_id": oid(5), "code": 552, "time": "2026/10/02 10:05:00.000"},
+		{"_id": oid(6), "code": 556, "time": "2026/10/01 09:58:30.000"},
+		// 地震感知情報は started_at ごとに 1 件
+		{"_id": oid(7), "code": 9611, "confidence": 0.95, "started_at": "2026/10/01 09:58:10.000", "time": "2026/10/01 09:58:20.000"},
+		{"_id": oid(8), "code": 9611, "confidence": 0.95, "started_at": "2026/10/01 09:58:10.000", "time": "2026/10/01 09:58:40.000"},
+		{"_id": oid(9), "code": 9611, "confidence": 0.95, "started_at": "2026/10/02 09:58:10.000", "time": "2026/10/02 09:58:20.000"},
+	}
+}
+
+func TestMemoryCountArchive(t *testing.T) {
+	m := NewMemory(archiveFixtures()...)
+
+	tests := []struct {
+		name   string
+		page   Page
+		keyLen int
+		want   []ArchiveCount
+	}{
+		{
+			name:   "day",
+			keyLen: len("2006/01/02"),
+			want: []ArchiveCount{
+				{Key: "2026/09/30", Code: 551, Count: 1, MaxScale: 30},
+				{Key: "2026/10/01", Code: 551, Count: 2, MaxScale: 50},
+				{Key: "2026/10/01", Code: 556, Count: 1},
+				{Key: "2026/10/01", Code: 9611, Count: 1},
+				{Key: "2026/10/02", Code: 551, Count: 1},
+				{Key: "2026/10/02", Code: 552, Count: 1},
+				{Key: "2026/10/02", Code: 9611, Count: 1},
+			},
+		},
+		{
+			name:   "month in period",
+			page:   Page{Since: "2026/10/01", Until: "2026/11/01"},
+			keyLen: len("2006/01"),
+			want: []ArchiveCount{
+				{Key: "2026/10", Code: 551, Count: 3, MaxScale: 50},
+				{Key: "2026/10", Code: 552, Count: 1},
+				{Key: "2026/10", Code: 556, Count: 1},
+				{Key: "2026/10", Code: 9611, Count: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, err := m.CountArchive(context.Background(), tt.page, tt.keyLen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sortCounts(counts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CountArchive() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func sortCounts(counts []ArchiveCount) []ArchiveCount {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Key != counts[j].Key {
+			return counts[i].Key < counts[j].Key
+		}
+		return counts[i].Code < counts[j].Code
+	})
+	return counts
+}
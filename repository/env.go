@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 環境変数から接続する。FIXTURES があればインメモリ、なければ MongoDB を使う。
+// close は終了時に呼ぶこと
+func FromEnv() (repo Repository, close func(), err error) {
+	if fixtures := os.Getenv("FIXTURES"); fixtures != "" {
+		log.Printf("Fixtures: %v\n", fixtures)
+
+		memory, err := LoadMemory(fixtures)
+		if err != nil {
+			return nil, nil, err
+		}
+		return memory, func() {}, nil
+	}
+
+	mongodbUrl := os.Getenv("MONGODB_URL")
+	mongodbDatabase := os.Getenv("DATABASE")
+	mongodbCollection := os.Getenv("COLLECTION")
+
+	opts := options.Client().ApplyURI(mongodbUrl)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	log.Printf("Database %v, Collection: %v\n", mongodbDatabase, mongodbCollection)
+
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	whole := client.Database(mongodbDatabase).Collection(mongodbCollection)
+	jma := client.Database(mongodbDatabase).Collection("jma")
+	close = func() { client.Disconnect(context.Background()) }
+	return &Mongo{Whole: whole, Jma: jma}, close, nil
+}
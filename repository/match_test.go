@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/p2pquake/web-client/model"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMemorySaveUserquakeMatch(t *testing.T) {
+	m := NewMemory(
+		bson.M{"_id": oid(1), "code": 551, "time": "2026/10/01 10:00:00.000"},
+		bson.M{"_id": oid(2), "code": 9611, "confidence": 0.95, "started_at": "2026/10/01 10:00:10.000", "time": "2026/10/01 10:00:30.000"},
+	)
+	ctx := context.Background()
+
+	record := model.UserquakeMatchRecord{Found: true, Match: model.UserquakeMatch{EarthquakeID: oid(1).Hex(), Score: 0.8}, Settled: true}
+	if err := m.SaveUserquakeMatch(ctx, oid(2), record); err != nil {
+		t.Fatal(err)
+	}
+	items, err := m.FindUserquakes(ctx, Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uq, _ := model.ToUserquake(items[0])
+	if uq.Match == nil || *uq.Match != record.Match {
+		t.Errorf("match = %+v, want %+v", uq.Match, record.Match)
+	}
+
+	// 地震感知情報以外には保存しない
+	for _, n := range []byte{1, 3} {
+		if err := m.SaveUserquakeMatch(ctx, oid(n), record); !errors.Is(err, ErrNotFound) {
+			t.Errorf("SaveUserquakeMatch(%d) = %v, want ErrNotFound", n, err)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/p2pquake/web-client/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MongoDB を使わずに動かすためのインメモリ実装
+type Memory struct {
+	mu    sync.RWMutex
+	items []bson.M // 挿入順（$natural と同じ）
+	jma   []bson.M
+
+	watchers []chan bson.M
+}
+
+func NewMemory(items ...bson.M) *Memory {
+	m := &Memory{}
+	for _, item := range items {
+		m.Insert(item)
+	}
+	return m
+}
+
+// JSON フィクスチャ（ドキュメントの配列）を読み込む。path がディレクトリなら *.json をすべて読み込み、
+// jma サブディレクトリの *.json は気象庁の電文として読み込む
+func LoadMemory(path string) (*Memory, error) {
+	files := []string{path}
+	var jmaFiles []string
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+			return nil, err
+		}
+		if jmaFiles, err = filepath.Glob(filepath.Join(path, "jma", "*.json")); err != nil {
+			return nil, err
+		}
+	}
+
+	m := &Memory{}
+	for _, file := range files {
+		items, err := readFixture(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, item := range items {
+			m.Insert(item)
+		}
+	}
+	for _, file := range jmaFiles {
+		items, err := readFixture(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, item := range items {
+			m.InsertBulletin(item)
+		}
+	}
+	return m, nil
+}
+
+func readFixture(file string) ([]bson.M, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(b, &raws); err != nil {
+		return nil, err
+	}
+
+	items := make([]bson.M, 0, len(raws))
+	for _, raw := range raws {
+		var item bson.M
+		if err := bson.UnmarshalExtJSON(raw, false, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// _id を ObjectID に揃えて追加する
+func (m *Memory) Insert(item bson.M) bson.M {
+	switch id := item["_id"].(type) {
+	case primitive.ObjectID:
+	case string:
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			oid = primitive.NewObjectID()
+		}
+		item["_id"] = oid
+	default:
+		// P2P地震情報 API の JSON は id を持つ
+		if s, ok := item["id"].(string); ok {
+			if oid, err := primitive.ObjectIDFromHex(s); err == nil {
+				item["_id"] = oid
+				break
+			}
+		}
+		item["_id"] = primitive.NewObjectID()
+	}
+
+	m.mu.Lock()
+	m.items = append(m.items, item)
+	if Watchable(item) {
+		watchers := m.watchers[:0]
+		for _, w := range m.watchers {
+			select {
+			case w <- copyM(item):
+				watchers = append(watchers, w)
+			default:
+				// 受け取れない watcher は閉じて、取りこぼしたことを知らせる
+				close(w)
+			}
+		}
+		m.watchers = watchers
+	}
+	m.mu.Unlock()
+	return item
+}
+
+func (m *Memory) InsertBulletin(item bson.M) bson.M {
+	if _, ok := item["_id"].(primitive.ObjectID); !ok {
+		item["_id"] = primitive.NewObjectID()
+	}
+
+	m.mu.Lock()
+	m.jma = append(m.jma, item)
+	m.mu.Unlock()
+	return item
+}
+
+// 新しい順に条件に合うものを返す
+func (m *Memory) filter(match func(bson.M) bool) []bson.M {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var items []bson.M
+	for i := len(m.items) - 1; i >= 0; i-- {
+		if match(m.items[i]) {
+			items = append(items, copyM(m.items[i]))
+		}
+	}
+	return items
+}
+
+func (m *Memory) FindJmas(ctx context.Context, page Page) ([]bson.M, error) {
+	items := m.filter(func(item bson.M) bool { return isJma(item) && page.match(item) })
+	SortNewest(items)
+	return limit(items, page), nil
+}
+
+func (m *Memory) FindUserquakes(ctx context.Context, page Page) ([]bson.M, error) {
+	if len(page.codes([]int{userquakeCode})) == 0 {
+		return nil, nil
+	}
+
+	items := m.filter(isUserquake)
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := str(items[i], "started_at"), str(items[j], "started_at")
+		if a != b {
+			return (a > b) != page.ascending()
+		}
+		return items[i]["_id"].(primitive.ObjectID).Hex() > items[j]["_id"].(primitive.ObjectID).Hex()
+	})
+
+	i := 0
+	items = collectUserquakes(func() (bson.M, bool) {
+		if i >= len(items) {
+			return nil, false
+		}
+		i++
+		return items[i-1], true
+	}, page)
+	if page.ascending() {
+		reverse(items)
+	}
+	return items, nil
+}
+
+func (m *Memory) SearchEarthquakes(ctx context.Context, f EarthquakeFilter, page Page) ([]bson.M, error) {
+	items := m.filter(func(item bson.M) bool { return f.match(item) && page.match(item) })
+	SortNewest(items)
+	return limit(items, page), nil
+}
+
+func (m *Memory) EachEarthquake(ctx context.Context, f EarthquakeFilter, page Page, fn func(bson.M) error) error {
+	items := m.filter(func(item bson.M) bool { return f.match(item) && page.match(item) })
+	SortNewest(items)
+	reverse(items)
+
+	for _, item := range items {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory) EarthquakeStats(ctx context.Context, f EarthquakeFilter, page Page) (*EarthquakeStats, error) {
+	items := m.filter(func(item bson.M) bool { return f.match(item) && page.match(item) })
+	SortNewest(items)
+	reverse(items)
+
+	// 同じ地震の情報は最後のものだけを数える
+	type key struct{ time, name string }
+	var keys []key
+	last := make(map[key]model.EarthquakeRecord)
+	for _, item := range items {
+		var eq model.EarthquakeRecord
+		bytes, _ := bson.Marshal(item)
+		bson.Unmarshal(bytes, &eq)
+
+		k := key{eq.Earthquake.Time, eq.Earthquake.Hypocenter.Name}
+		if _, ok := last[k]; !ok {
+			keys = append(keys, k)
+		}
+		last[k] = eq
+	}
+
+	stats := newEarthquakeStats()
+	days := make(map[DayCount]int)
+	for _, k := range keys {
+		eq := last[k]
+		day := eq.Earthquake.Time
+		if len(day) > 10 {
+			day = day[:10]
+		}
+		days[DayCount{Day: day, MaxScale: eq.Earthquake.MaxScale}]++
+		stats.Magnitudes[bin(eq.Earthquake.Hypocenter.Magnitude, MagnitudeBounds)]++
+		stats.Depths[bin(eq.Earthquake.Hypocenter.Depth, DepthBounds)]++
+	}
+
+	for d, count := range days {
+		d.Count = count
+		stats.Days = append(stats.Days, d)
+	}
+	sort.Slice(stats.Days, func(i, j int) bool {
+		if stats.Days[i].Day != stats.Days[j].Day {
+			return stats.Days[i].Day < stats.Days[j].Day
+		}
+		return stats.Days[i].MaxScale < stats.Days[j].MaxScale
+	})
+	return stats, nil
+}
+
+func (m *Memory) CountArchive(ctx context.Context, page Page, keyLen int) ([]ArchiveCount, error) {
+	jmas, err := m.FindJmas(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	userquakes, err := m.FindUserquakes(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return countArchive(append(jmas, userquakes...), keyLen), nil
+}
+
+func (m *Memory) FindByID(ctx context.Context, id string) (bson.M, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	items := m.filter(func(item bson.M) bool { return item["_id"] == oid })
+	if len(items) == 0 {
+		return nil, ErrNotFound
+	}
+	return items[0], nil
+}
+
+func (m *Memory) SaveUserquakeMatch(ctx context.Context, id primitive.ObjectID, match model.UserquakeMatchRecord) error {
+	// MongoDB から読み込んだときと同じく bson.M で持つ
+	bytes, err := bson.Marshal(match)
+	if err != nil {
+		return err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(bytes, &doc); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, item := range m.items {
+		if item["_id"] == id && model.ToInt(item["code"]) == userquakeCode {
+			item["match"] = doc
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (m *Memory) FindBulletins(ctx context.Context, titles []string, reportTime string) ([]bson.M, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var items []bson.M
+	for _, item := range m.jma {
+		if contains(titles, str(item, "title")) && str(item, "report_time") == reportTime {
+			items = append(items, copyM(item))
+		}
+	}
+	return items, nil
+}
+
+func (m *Memory) FindTimeseries(ctx context.Context, startedAt string) ([]bson.M, error) {
+	items := m.filter(func(item bson.M) bool {
+		return model.ToInt(item["code"]) == userquakeCode && str(item, "started_at") == startedAt
+	})
+	sort.SliceStable(items, func(i, j int) bool { return str(items[i], "updated_at") < str(items[j], "updated_at") })
+	return items, nil
+}
+
+// items は新しい順。After 指定時はカーソルに近い側から Limit 件を残す
+func limit(items []bson.M, page Page) []bson.M {
+	if page.Limit <= 0 || len(items) <= page.Limit {
+		return items
+	}
+	if page.ascending() {
+		return items[len(items)-page.Limit:]
+	}
+	return items[:page.Limit]
+}
+
+func isJma(item bson.M) bool {
+	code := model.ToInt(item["code"])
+	for _, c := range jmaCodes {
+		if code == c {
+			return true
+		}
+	}
+	return false
+}
+
+func isUserquake(item bson.M) bool {
+	confidence, _ := item["confidence"].(float64)
+	return model.ToInt(item["code"]) == userquakeCode && confidence > userquakeMinConfidence
+}
+
+func str(item bson.M, key string) string {
+	s, _ := item[key].(string)
+	return s
+}
+
+func toFloat(e interface{}) float64 {
+	if v, ok := e.(float64); ok {
+		return v
+	}
+	return float64(model.ToInt(e))
+}
+
+// 呼び出し側での書き換え（time の置き換えなど）が保持データに及ばないようにする
+func copyM(item bson.M) bson.M {
+	c := make(bson.M, len(item))
+	for k, v := range item {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/p2pquake/web-client/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Mongo struct {
+	Whole *mongo.Collection
+	Jma   *mongo.Collection
+}
+
+func (m *Mongo) FindJmas(ctx context.Context, page Page) ([]bson.M, error) {
+	codes := page.codes(jmaCodes)
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	opts := options.Find().SetSort(page.sort("time"))
+	if page.Limit > 0 {
+		opts.SetLimit(int64(page.Limit))
+	}
+	filter := page.filter("time")
+	filter["code"] = bson.M{"$in": codes}
+
+	cursor, err := m.Whole.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []bson.M
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	if page.ascending() {
+		reverse(items)
+	}
+
+	return items, nil
+}
+
+func (m *Mongo) FindUserquakes(ctx context.Context, page Page) ([]bson.M, error) {
+	if len(page.codes([]int{userquakeCode})) == 0 {
+		return nil, nil
+	}
+
+	filter := bson.M{
+		"code":       userquakeCode,
+		"confidence": bson.M{"$gt": userquakeMinConfidence},
+	}
+	if f := page.userquakeFilter(); len(f) > 0 {
+		filter["started_at"] = f
+	}
+
+	cursor, err := m.Whole.Find(ctx, filter, options.Find().SetSort(page.userquakeSort()))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var decodeErr error
+	items := collectUserquakes(func() (bson.M, bool) {
+		if !cursor.Next(ctx) {
+			return nil, false
+		}
+		var item bson.M
+		if decodeErr = cursor.Decode(&item); decodeErr != nil {
+			return nil, false
+		}
+		return item, true
+	}, page)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	if page.ascending() {
+		reverse(items)
+	}
+
+	return items, nil
+}
+
+func (m *Mongo) SearchEarthquakes(ctx context.Context, f EarthquakeFilter, page Page) ([]bson.M, error) {
+	opts := options.Find().SetSort(page.sort("time"))
+	if page.Limit > 0 {
+		opts.SetLimit(int64(page.Limit))
+	}
+	filter := page.filter("time")
+	for k, v := range f.query() {
+		filter[k] = v
+	}
+
+	cursor, err := m.Whole.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []bson.M
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	if page.ascending() {
+		reverse(items)
+	}
+
+	return items, nil
+}
+
+func (m *Mongo) EachEarthquake(ctx context.Context, f EarthquakeFilter, page Page, fn func(bson.M) error) error {
+	filter := page.filter("time")
+	for k, v := range f.query() {
+		filter[k] = v
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := m.Whole.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var item bson.M
+		if err := cursor.Decode(&item); err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (m *Mongo) CountArchive(ctx context.Context, page Page, keyLen int) ([]ArchiveCount, error) {
+	var counts []ArchiveCount
+
+	if codes := page.codes(jmaCodes); len(codes) > 0 {
+		filter := page.filter("time")
+		filter["code"] = bson.M{"$in": codes}
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$group", Value: bson.M{
+				"_id":      bson.M{"key": bson.M{"$substrCP": bson.A{"$time", 0, keyLen}}, "code": "$code"},
+				"count":    bson.M{"$sum": 1},
+				"maxScale": bson.M{"$max": "$earthquake.maxScale"},
+			}}},
+			// 地震情報以外と、最大震度が不明（-1）のものは 0 にする
+			{{Key: "$project", Value: bson.M{"_id": 0, "key": "$_id.key", "code": "$_id.code", "count": 1, "maxScale": bson.M{"$max": bson.A{"$maxScale", 0}}}}},
+		}
+		cursor, err := m.Whole.Aggregate(ctx, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		if err := cursor.All(ctx, &counts); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(page.codes([]int{userquakeCode})) > 0 {
+		filter := bson.M{
+			"code":       userquakeCode,
+			"confidence": bson.M{"$gt": userquakeMinConfidence},
+		}
+		if f := page.userquakeFilter(); len(f) > 0 {
+			filter["started_at"] = f
+		}
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			// started_at ごとに 1 件
+			{{Key: "$group", Value: bson.M{"_id": "$started_at"}}},
+			{{Key: "$group", Value: bson.M{
+				"_id":   bson.M{"$substrCP": bson.A{"$_id", 0, keyLen}},
+				"count": bson.M{"$sum": 1},
+			}}},
+			{{Key: "$project", Value: bson.M{"_id": 0, "key": "$_id", "code": bson.M{"$literal": userquakeCode}, "count": 1}}},
+		}
+		cursor, err := m.Whole.Aggregate(ctx, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		var userquakes []ArchiveCount
+		if err := cursor.All(ctx, &userquakes); err != nil {
+			return nil, err
+		}
+		counts = append(counts, userquakes...)
+	}
+
+	return counts, nil
+}
+
+func (m *Mongo) FindByID(ctx context.Context, id string) (bson.M, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	var item bson.M
+	err = m.Whole.FindOne(ctx, bson.M{"_id": oid}).Decode(&item)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (m *Mongo) SaveUserquakeMatch(ctx context.Context, id primitive.ObjectID, match model.UserquakeMatchRecord) error {
+	result, err := m.Whole.UpdateOne(ctx, bson.M{"_id": id, "code": userquakeCode}, bson.M{"$set": bson.M{"match": match}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (m *Mongo) FindBulletins(ctx context.Context, titles []string, reportTime string) ([]bson.M, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := m.Jma.Find(
+		ctx,
+		bson.M{
+			"title":       bson.M{"$in": titles},
+			"report_time": reportTime,
+		}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []bson.M
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (m *Mongo) FindTimeseries(ctx context.Context, startedAt string) ([]bson.M, error) {
+	opts := options.FindOptions{Sort: bson.D{{Key: "updated_at", Value: 1}}}
+	cursor, err := m.Whole.Find(
+		ctx,
+		bson.M{
+			"code":       userquakeCode,
+			"started_at": startedAt,
+		}, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []bson.M
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (m *Mongo) EarthquakeStats(ctx context.Context, f EarthquakeFilter, page Page) (*EarthquakeStats, error) {
+	filter := page.filter("time")
+	for k, v := range f.query() {
+		filter[k] = v
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "time", Value: 1}, {Key: "_id", Value: 1}}}},
+		// 同じ地震の情報は最後のものだけを数える
+		{{Key: "$group", Value: bson.M{
+			"_id":  bson.M{"time": "$earthquake.time", "name": "$earthquake.hypocenter.name"},
+			"last": bson.M{"$last": "$earthquake"},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"days": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":   bson.M{"day": bson.M{"$substrCP": bson.A{"$last.time", 0, 10}}, "scale": "$last.maxScale"},
+					"count": bson.M{"$sum": 1},
+				}},
+				bson.M{"$project": bson.M{"_id": 0, "day": "$_id.day", "scale": "$_id.scale", "count": 1}},
+				bson.M{"$sort": bson.D{{Key: "day", Value: 1}, {Key: "scale", Value: 1}}},
+			},
+			"magnitudes": bson.A{bson.M{"$bucket": bson.M{
+				"groupBy":    "$last.hypocenter.magnitude",
+				"boundaries": MagnitudeBounds,
+				"default":    "unknown",
+			}}},
+			"depths": bson.A{bson.M{"$bucket": bson.M{
+				"groupBy":    "$last.hypocenter.depth",
+				"boundaries": DepthBounds,
+				"default":    "unknown",
+			}}},
+		}}},
+	}
+
+	cursor, err := m.Whole.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	type bucket struct {
+		ID    interface{} `bson:"_id"`
+		Count int         `bson:"count"`
+	}
+	var results []struct {
+		Days       []DayCount `bson:"days"`
+		Magnitudes []bucket   `bson:"magnitudes"`
+		Depths     []bucket   `bson:"depths"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	stats := newEarthquakeStats()
+	if len(results) == 0 {
+		return stats, nil
+	}
+	stats.Days = results[0].Days
+
+	// 区間は下限の値、不明は "unknown" になる
+	for _, b := range results[0].Magnitudes {
+		i := len(MagnitudeBounds) - 1
+		if _, ok := b.ID.(string); !ok {
+			i = bin(toFloat(b.ID), MagnitudeBounds)
+		}
+		stats.Magnitudes[i] += b.Count
+	}
+	for _, b := range results[0].Depths {
+		i := len(DepthBounds) - 1
+		if _, ok := b.ID.(string); !ok {
+			i = bin(model.ToInt(b.ID), DepthBounds)
+		}
+		stats.Depths[i] += b.Count
+	}
+	return stats, nil
+}
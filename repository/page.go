@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/p2pquake/web-client/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ページングの基準位置（time と _id の組）
+type Cursor struct {
+	Time string
+	ID   primitive.ObjectID
+}
+
+type Page struct {
+	Since  string  // time がこれ以降のもの
+	Until  string  // time がこれより前のもの
+	Before *Cursor // カーソルより古いもの
+	After  *Cursor // カーソルより新しいもの
+	Limit  int     // 0 なら無制限
+	Codes  []int   // 絞り込む情報のコード（空なら全て）
+}
+
+func CursorOf(item bson.M) Cursor {
+	c := Cursor{Time: str(item, "time")}
+	c.ID, _ = item["_id"].(primitive.ObjectID)
+	return c
+}
+
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Time + "|" + c.ID.Hex()))
+}
+
+func ParseCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	t, id, ok := strings.Cut(string(b), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{Time: t, ID: oid}, nil
+}
+
+// a が b より新しいか
+func Newer(a, b Cursor) bool {
+	if a.Time != b.Time {
+		return a.Time > b.Time
+	}
+	return a.ID.Hex() > b.ID.Hex()
+}
+
+// 新しい順に並び替える
+func SortNewest(items []bson.M) {
+	sort.SliceStable(items, func(i, j int) bool { return Newer(CursorOf(items[i]), CursorOf(items[j])) })
+}
+
+func (p Page) ascending() bool {
+	return p.After != nil && p.Before == nil
+}
+
+// Codes のうち対象に含まれるもの。Codes が空なら対象すべて
+func (p Page) codes(targets []int) []int {
+	if len(p.Codes) == 0 {
+		return targets
+	}
+	var codes []int
+	for _, c := range targets {
+		for _, code := range p.Codes {
+			if c == code {
+				codes = append(codes, c)
+				break
+			}
+		}
+	}
+	return codes
+}
+
+func (p Page) match(item bson.M) bool {
+	if len(p.Codes) > 0 && len(p.codes([]int{model.ToInt(item["code"])})) == 0 {
+		return false
+	}
+	c := CursorOf(item)
+	if p.Since != "" && c.Time < p.Since {
+		return false
+	}
+	if p.Until != "" && c.Time >= p.Until {
+		return false
+	}
+	if p.Before != nil && !Newer(*p.Before, c) {
+		return false
+	}
+	if p.After != nil && !Newer(c, *p.After) {
+		return false
+	}
+	return true
+}
+
+// time 基準のカーソル条件を MongoDB のフィルタにする
+func (p Page) filter(key string) bson.M {
+	var conds bson.A
+	if p.Since != "" {
+		conds = append(conds, bson.M{key: bson.M{"$gte": p.Since}})
+	}
+	if p.Until != "" {
+		conds = append(conds, bson.M{key: bson.M{"$lt": p.Until}})
+	}
+	if p.Before != nil {
+		conds = append(conds, bson.M{"$or": bson.A{
+			bson.M{key: bson.M{"$lt": p.Before.Time}},
+			bson.M{key: p.Before.Time, "_id": bson.M{"$lt": p.Before.ID}},
+		}})
+	}
+	if p.After != nil {
+		conds = append(conds, bson.M{"$or": bson.A{
+			bson.M{key: bson.M{"$gt": p.After.Time}},
+			bson.M{key: p.After.Time, "_id": bson.M{"$gt": p.After.ID}},
+		}})
+	}
+	if len(conds) == 0 {
+		return bson.M{}
+	}
+	return bson.M{"$and": conds}
+}
+
+func (p Page) sort(key string) bson.D {
+	order := -1
+	if p.ascending() {
+		order = 1
+	}
+	return bson.D{{Key: key, Value: order}, {Key: "_id", Value: order}}
+}
+
+// 地震感知情報を started_at ごとにまとめ、カーソル条件に合うものを Limit 件まで集める。
+// next は started_at 順（同じ started_at 内では _id 降順）にドキュメントを返すこと
+func collectUserquakes(next func() (bson.M, bool), p Page) []bson.M {
+	var items []bson.M
+	var last string
+	first := true
+	for {
+		item, ok := next()
+		if !ok {
+			break
+		}
+
+		startedAt := str(item, "started_at")
+		if !first && startedAt == last {
+			continue
+		}
+		first = false
+		last = startedAt
+
+		// started_at ごとに最新の 1 件を代表とする
+		item["time"] = startedAt
+		if !p.match(item) {
+			continue
+		}
+		items = append(items, item)
+		if p.Limit > 0 && len(items) >= p.Limit {
+			break
+		}
+	}
+	return items
+}
+
+// 地震感知情報の started_at に対する絞り込み（_id は代表を決めた後で比較する）
+func (p Page) userquakeFilter() bson.M {
+	f := bson.M{}
+	if p.Since != "" {
+		f["$gte"] = p.Since
+	}
+	if p.Until != "" {
+		f["$lt"] = p.Until
+	}
+	if p.Before != nil {
+		f["$lte"] = p.Before.Time
+	}
+	if p.After != nil {
+		if s, ok := f["$gte"].(string); !ok || p.After.Time > s {
+			f["$gte"] = p.After.Time
+		}
+	}
+	return f
+}
+
+func (p Page) userquakeSort() bson.D {
+	order := -1
+	if p.ascending() {
+		order = 1
+	}
+	return bson.D{{Key: "started_at", Value: order}, {Key: "_id", Value: -1}}
+}
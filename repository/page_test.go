@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func oid(n byte) primitive.ObjectID {
+	var id primitive.ObjectID
+	id[len(id)-1] = n
+	return id
+}
+
+func ids(items []bson.M) []byte {
+	var result []byte
+	for _, item := range items {
+		id := item["_id"].(primitive.ObjectID)
+		result = append(result, id[len(id)-1])
+	}
+	return result
+}
+
+func TestCursorString(t *testing.T) {
+	c := Cursor{Time: "2026/10/17 10:00:05.000", ID: oid(1)}
+	got, err := ParseCursor(c.String())
+	if err != nil || *got != c {
+		t.Errorf("ParseCursor(%q) = %v, %v, want %v", c.String(), got, err, c)
+	}
+
+	for _, s := range []string{"", "!!", "MjAyNg", "MjAyNnx4eXo"} {
+		if _, err := ParseCursor(s); err != ErrInvalidCursor {
+			t.Errorf("ParseCursor(%q) error = %v, want ErrInvalidCursor", s, err)
+		}
+	}
+}
+
+func TestFindJmasPage(t *testing.T) {
+	// 3 と 4 は受信時刻が同じ。_id の大きい方を新しいとする
+	m := NewMemory(
+		bson.M{"_id": oid(1), "code": 551, "time": "2026/10/17 10:00:01.000"},
+		bson.M{"_id": oid(2), "code": 556, "time": "2026/10/17 10:00:02.000"},
+		bson.M{"_id": oid(4), "code": 551, "time": "2026/10/17 10:00:03.000"},
+		bson.M{"_id": oid(3), "code": 552, "time": "2026/10/17 10:00:03.000"},
+		bson.M{"_id": oid(5), "code": 551, "time": "2026/10/17 10:00:05.000"},
+		bson.M{"_id": oid(6), "code": 9611, "time": "2026/10/17 10:00:06.000"},
+	)
+	cursor := func(n byte, t string) *Cursor { return &Cursor{Time: t, ID: oid(n)} }
+
+	tests := []struct {
+		name string
+		page Page
+		want []byte
+	}{
+		{name: "all", page: Page{}, want: []byte{5, 4, 3, 2, 1}},
+		{name: "limit", page: Page{Limit: 2}, want: []byte{5, 4}},
+		{name: "before", page: Page{Before: cursor(4, "2026/10/17 10:00:03.000"), Limit: 2}, want: []byte{3, 2}},
+		{name: "after", page: Page{After: cursor(2, "2026/10/17 10:00:02.000"), Limit: 2}, want: []byte{4, 3}},
+		{name: "after the newest", page: Page{After: cursor(5, "2026/10/17 10:00:05.000"), Limit: 2}},
+		{name: "since and until", page: Page{Since: "2026/10/17 10:00:02", Until: "2026/10/17 10:00:05"}, want: []byte{4, 3, 2}},
+		{name: "codes", page: Page{Codes: []int{551, 9611}}, want: []byte{5, 4, 1}},
+		{name: "no jma codes", page: Page{Codes: []int{9611}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := m.FindJmas(context.Background(), tt.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindJmas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindUserquakesPage(t *testing.T) {
+	// started_at ごとに最新（_id の大きいもの）の 1 件
+	m := NewMemory(
+		bson.M{"_id": oid(1), "code": 9611, "confidence": 0.95, "started_at": "2026/10/17 10:00:00.000"},
+		bson.M{"_id": oid(2), "code": 9611, "confidence": 0.97, "started_at": "2026/10/17 10:00:00.000"},
+		bson.M{"_id": oid(3), "code": 9611, "confidence": 0.5, "started_at": "2026/10/17 11:00:00.000"},
+		bson.M{"_id": oid(4), "code": 9611, "confidence": 0.95, "started_at": "2026/10/17 12:00:00.000"},
+		bson.M{"_id": oid(5), "code": 9611, "confidence": 0.95, "started_at": "2026/10/17 13:00:00.000"},
+	)
+
+	tests := []struct {
+		name string
+		page Page
+		want []byte
+	}{
+		{name: "all", page: Page{}, want: []byte{5, 4, 2}},
+		{name: "limit", page: Page{Limit: 1}, want: []byte{5}},
+		{name: "before", page: Page{Before: &Cursor{Time: "2026/10/17 12:00:00.000", ID: oid(4)}}, want: []byte{2}},
+		{name: "after", page: Page{After: &Cursor{Time: "2026/10/17 10:00:00.000", ID: oid(2)}, Limit: 1}, want: []byte{4}},
+		{name: "other codes", page: Page{Codes: []int{551}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := m.FindUserquakes(context.Background(), tt.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindUserquakes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
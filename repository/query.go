@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+// 検索・CSV 書き出しに共通するパラメータ（hypocenter, pref, type, min_scale, max_scale,
+// min_mag, max_mag, min_depth, max_depth, from, to）を読み取る。日付は JST
+func ParseEarthquakeQuery(q url.Values) (EarthquakeFilter, Page, error) {
+	filter := EarthquakeFilter{
+		Hypocenter: strings.TrimSpace(q.Get("hypocenter")),
+		Prefs:      nonEmpty(q["pref"]),
+		IssueTypes: nonEmpty(q["type"]),
+	}
+	page := Page{}
+
+	var err error
+	if filter.MinScale, err = intParam(q, "min_scale"); err != nil {
+		return filter, page, err
+	}
+	if filter.MaxScale, err = intParam(q, "max_scale"); err != nil {
+		return filter, page, err
+	}
+	if filter.MinMagnitude, err = floatPtrParam(q, "min_mag"); err != nil {
+		return filter, page, err
+	}
+	if filter.MaxMagnitude, err = floatPtrParam(q, "max_mag"); err != nil {
+		return filter, page, err
+	}
+	if filter.MinDepth, err = intPtrParam(q, "min_depth"); err != nil {
+		return filter, page, err
+	}
+	if filter.MaxDepth, err = intPtrParam(q, "max_depth"); err != nil {
+		return filter, page, err
+	}
+
+	if from := q.Get("from"); from != "" {
+		t, err := time.ParseInLocation("2006-01-02", from, model.JST)
+		if err != nil {
+			return filter, page, errors.New("invalid from")
+		}
+		page.Since = t.Format("2006/01/02 15:04:05")
+	}
+	if to := q.Get("to"); to != "" {
+		t, err := time.ParseInLocation("2006-01-02", to, model.JST)
+		if err != nil {
+			return filter, page, errors.New("invalid to")
+		}
+		page.Until = t.AddDate(0, 0, 1).Format("2006/01/02 15:04:05")
+	}
+
+	return filter, page, nil
+}
+
+func intParam(q url.Values, key string) (int, error) {
+	v := q.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.New("invalid " + key)
+	}
+	return i, nil
+}
+
+func intPtrParam(q url.Values, key string) (*int, error) {
+	if q.Get(key) == "" {
+		return nil, nil
+	}
+	i, err := intParam(q, key)
+	return &i, err
+}
+
+func floatPtrParam(q url.Values, key string) (*float64, error) {
+	v := q.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, errors.New("invalid " + key)
+	}
+	return &f, nil
+}
+
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
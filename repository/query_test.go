@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseEarthquakeQuery(t *testing.T) {
+	mag, depth := 4.5, 0
+	q := url.Values{
+		"hypocenter": {" 能登 "},
+		"pref":       {"石川県", "", "富山県"},
+		"type":       {"DetailScale"},
+		"min_scale":  {"30"},
+		"max_scale":  {"50"},
+		"min_mag":    {"4.5"},
+		"max_depth":  {"0"},
+		"from":       {"2026-10-01"},
+		"to":         {"2026-10-31"},
+	}
+	filter, page, err := ParseEarthquakeQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := EarthquakeFilter{
+		MinScale: 30, MaxScale: 50, MinMagnitude: &mag, MaxDepth: &depth,
+		Hypocenter: "能登", Prefs: []string{"石川県", "富山県"}, IssueTypes: []string{"DetailScale"},
+	}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("filter = %+v, want %+v", filter, want)
+	}
+	// 日付は JST の 0 時から翌日 0 時まで
+	if page.Since != "2026/10/01 00:00:00" || page.Until != "2026/11/01 00:00:00" || page.Limit != 0 {
+		t.Errorf("page = %+v", page)
+	}
+
+	for _, key := range []string{"min_scale", "max_scale", "min_mag", "max_mag", "min_depth", "max_depth", "from", "to"} {
+		if _, _, err := ParseEarthquakeQuery(url.Values{key: {"x"}}); err == nil || err.Error() != "invalid "+key {
+			t.Errorf("%s=x: err = %v, want invalid %s", key, err, key)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/p2pquake/web-client/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrInvalidID = errors.New("invalid id")
+)
+
+// 一覧系はいずれも新しい順に返す
+type Repository interface {
+	// 地震情報・津波予報・緊急地震速報（警報）
+	FindJmas(ctx context.Context, page Page) ([]bson.M, error)
+	// 地震感知情報（started_at ごとに最新の 1 件、time は started_at に置き換える）
+	FindUserquakes(ctx context.Context, page Page) ([]bson.M, error)
+	FindByID(ctx context.Context, id string) (bson.M, error)
+	// 条件に合う地震情報
+	SearchEarthquakes(ctx context.Context, filter EarthquakeFilter, page Page) ([]bson.M, error)
+	// 条件に合う地震情報を古い順に 1 件ずつ fn に渡す。fn がエラーを返したら中断する
+	EachEarthquake(ctx context.Context, filter EarthquakeFilter, page Page, fn func(bson.M) error) error
+	// 条件に合う地震情報の日ごと・最大震度ごとの件数と、マグニチュード・深さの度数分布
+	EarthquakeStats(ctx context.Context, filter EarthquakeFilter, page Page) (*EarthquakeStats, error)
+	// FindJmas / FindUserquakes と同じ対象の、受信時刻の先頭 keyLen 文字（"2006/01" なら月）・コードごとの件数
+	CountArchive(ctx context.Context, page Page, keyLen int) ([]ArchiveCount, error)
+	// 気象庁の電文（表題のいずれかに一致し、発表時刻が同じもの）
+	FindBulletins(ctx context.Context, titles []string, reportTime string) ([]bson.M, error)
+	// 同じ started_at を持つ地震感知情報（updated_at 昇順）
+	FindTimeseries(ctx context.Context, startedAt string) ([]bson.M, error)
+	// 新しく追加された情報（FindJmas / FindUserquakes と同じ条件）を ctx が終わるまで流す。
+	// ctx が終わる前に閉じられた場合は取りこぼしがある
+	Watch(ctx context.Context) (<-chan bson.M, error)
+	// 地震感知情報に、地震情報との対応を調べた結果を保存する（match フィールド）
+	SaveUserquakeMatch(ctx context.Context, id primitive.ObjectID, match model.UserquakeMatchRecord) error
+}
+
+var jmaCodes = []int{551, 552, 556}
+
+const (
+	userquakeCode          = 9611
+	userquakeMinConfidence = 0.9
+)
+
+// Watch で流す対象か
+func Watchable(item bson.M) bool {
+	return isJma(item) || isUserquake(item)
+}
+
+func reverse(items []bson.M) {
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+}
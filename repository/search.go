@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/p2pquake/web-client/model"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// 地震情報（551）の検索条件。ゼロ値・nil の項目は条件にしない
+type EarthquakeFilter struct {
+	MinScale     int
+	MaxScale     int
+	MinMagnitude *float64
+	MaxMagnitude *float64
+	MinDepth     *int
+	MaxDepth     *int
+	Hypocenter   string   // 震源名の部分一致
+	Prefs        []string // いずれかの都道府県で震度観測
+	IssueTypes   []string
+}
+
+func (f EarthquakeFilter) query() bson.M {
+	q := bson.M{"code": 551}
+	if f.MinScale > 0 || f.MaxScale > 0 {
+		r := bson.M{}
+		if f.MinScale > 0 {
+			r["$gte"] = f.MinScale
+		}
+		if f.MaxScale > 0 {
+			r["$lte"] = f.MaxScale
+		}
+		q["earthquake.maxScale"] = r
+	}
+	if r := ptrRangeQuery(f.MinMagnitude, f.MaxMagnitude); len(r) > 0 {
+		q["earthquake.hypocenter.magnitude"] = r
+	}
+	if r := ptrRangeQuery(f.MinDepth, f.MaxDepth); len(r) > 0 {
+		q["earthquake.hypocenter.depth"] = r
+	}
+	if f.Hypocenter != "" {
+		q["earthquake.hypocenter.name"] = bson.M{"$regex": regexp.QuoteMeta(f.Hypocenter)}
+	}
+	if len(f.Prefs) > 0 {
+		q["points.pref"] = bson.M{"$in": f.Prefs}
+	}
+	if len(f.IssueTypes) > 0 {
+		q["issue.type"] = bson.M{"$in": f.IssueTypes}
+	}
+	return q
+}
+
+func (f EarthquakeFilter) match(item bson.M) bool {
+	if model.ToInt(item["code"]) != 551 {
+		return false
+	}
+
+	var eq model.EarthquakeRecord
+	bytes, _ := bson.Marshal(item)
+	bson.Unmarshal(bytes, &eq)
+
+	h := eq.Earthquake.Hypocenter
+	if f.MinScale > 0 && eq.Earthquake.MaxScale < f.MinScale {
+		return false
+	}
+	if f.MaxScale > 0 && eq.Earthquake.MaxScale > f.MaxScale {
+		return false
+	}
+	if f.MinMagnitude != nil && h.Magnitude < *f.MinMagnitude {
+		return false
+	}
+	if f.MaxMagnitude != nil && h.Magnitude > *f.MaxMagnitude {
+		return false
+	}
+	if f.MinDepth != nil && h.Depth < *f.MinDepth {
+		return false
+	}
+	if f.MaxDepth != nil && h.Depth > *f.MaxDepth {
+		return false
+	}
+	if f.Hypocenter != "" && !strings.Contains(h.Name, f.Hypocenter) {
+		return false
+	}
+	if len(f.IssueTypes) > 0 && !contains(f.IssueTypes, eq.Issue.Type) {
+		return false
+	}
+	if len(f.Prefs) > 0 {
+		found := false
+		for _, p := range eq.Points {
+			if contains(f.Prefs, p.Pref) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func ptrRangeQuery[T int | float64](min, max *T) bson.M {
+	r := bson.M{}
+	if min != nil {
+		r["$gte"] = *min
+	}
+	if max != nil {
+		r["$lte"] = *max
+	}
+	return r
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type earthquakeDoc struct {
+	id         byte
+	time       string // 受信時刻
+	issueType  string
+	origin     string // 発生時刻
+	hypocenter string
+	magnitude  float64
+	depth      int
+	maxScale   int
+	prefs      []string
+}
+
+func (d earthquakeDoc) bson() bson.M {
+	var points bson.A
+	for _, pref := range d.prefs {
+		points = append(points, bson.M{"pref": pref, "addr": pref + "の観測点", "isArea": false, "scale": d.maxScale})
+	}
+	return bson.M{
+		"_id":   oid(d.id),
+		"code":  551,
+		"time":  d.time,
+		"issue": bson.M{"type": d.issueType, "time": d.time[:19]},
+		"earthquake": bson.M{
+			"time":     d.origin,
+			"maxScale": d.maxScale,
+			"hypocenter": bson.M{
+				"name":      d.hypocenter,
+				"magnitude": d.magnitude,
+				"depth":     d.depth,
+				"latitude":  37.5,
+				"longitude": 137.2,
+			},
+		},
+		"points": points,
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestSearchEarthquakes(t *testing.T) {
+	m := NewMemory(
+		earthquakeDoc{1, "2026/10/01 10:00:00.000", "DetailScale", "2026/10/01 09:58:00", "石川県能登地方", 5.6, 10, 50, []string{"石川県", "富山県"}}.bson(),
+		earthquakeDoc{2, "2026/10/02 10:00:00.000", "DetailScale", "2026/10/02 09:58:00", "東京都２３区", 4.1, 30, 30, []string{"東京都"}}.bson(),
+		earthquakeDoc{3, "2026/10/03 10:00:00.000", "DetailScale", "2026/10/03 09:58:00", "京都府南部", 3.2, 10, 20, []string{"京都府"}}.bson(),
+		earthquakeDoc{4, "2026/10/04 10:00:00.000", "ScalePrompt", "2026/10/04 09:58:00", "", -1, -1, 46, []string{"茨城県"}}.bson(),
+		earthquakeDoc{5, "2026/10/05 10:00:00.000", "Destination", "2026/10/05 09:58:00", "十勝沖", 6.8, 60, -1, nil}.bson(),
+		bson.M{"_id": oid(6), "code": 552, "time": "2026/10/05 10:01:00.000"},
+	)
+
+	tests := []struct {
+		name   string
+		filter EarthquakeFilter
+		want   []byte
+	}{
+		{name: "all", want: []byte{5, 4, 3, 2, 1}},
+		{name: "min scale", filter: EarthquakeFilter{MinScale: 30}, want: []byte{4, 2, 1}},
+		{name: "max scale", filter: EarthquakeFilter{MinScale: 10, MaxScale: 30}, want: []byte{3, 2}},
+		{name: "magnitude", filter: EarthquakeFilter{MinMagnitude: ptr(4.0), MaxMagnitude: ptr(6.0)}, want: []byte{2, 1}},
+		{name: "depth", filter: EarthquakeFilter{MinDepth: ptr(20)}, want: []byte{5, 2}},
+		{name: "hypocenter", filter: EarthquakeFilter{Hypocenter: "能登"}, want: []byte{1}},
+		{name: "hypocenter partially", filter: EarthquakeFilter{Hypocenter: "京都"}, want: []byte{3, 2}},
+		{name: "hypocenter is not a regexp", filter: EarthquakeFilter{Hypocenter: ".*"}},
+		{name: "prefs", filter: EarthquakeFilter{Prefs: []string{"富山県", "東京都"}}, want: []byte{2, 1}},
+		{name: "prefs exactly", filter: EarthquakeFilter{Prefs: []string{"京都府"}}, want: []byte{3}},
+		{name: "issue types", filter: EarthquakeFilter{IssueTypes: []string{"ScalePrompt", "Destination"}}, want: []byte{5, 4}},
+		{name: "combined", filter: EarthquakeFilter{MinScale: 20, Prefs: []string{"京都府", "石川県"}, IssueTypes: []string{"DetailScale"}}, want: []byte{3, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := m.SearchEarthquakes(context.Background(), tt.filter, Page{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchEarthquakes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// ページの条件と組み合わせる
+	items, err := m.SearchEarthquakes(context.Background(), EarthquakeFilter{MinScale: 10}, Page{Until: "2026/10/04", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(items), []byte{3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchEarthquakes() with page = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+package repository
+
+// 地震情報の集計。訂正などで同じ地震（発生時刻と震源名が同じ）の情報が複数あれば最後のものだけを数える
+type EarthquakeStats struct {
+	Days       []DayCount // 日付・最大震度順
+	Magnitudes []int      // MagnitudeBounds の区間ごとの件数。最後の要素は不明（範囲外を含む）
+	Depths     []int      // DepthBounds の区間ごとの件数。最後の要素は不明（範囲外を含む）
+}
+
+type DayCount struct {
+	Day      string `bson:"day" json:"day"` // 発生日（2006/01/02）
+	MaxScale int    `bson:"scale" json:"maxScale"`
+	Count    int    `bson:"count" json:"count"`
+}
+
+// 度数分布の区間の境界（下限を含み上限を含まない）
+var (
+	MagnitudeBounds = []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 10}
+	DepthBounds     = []int{0, 10, 20, 30, 50, 100, 200, 300, 700}
+)
+
+func newEarthquakeStats() *EarthquakeStats {
+	return &EarthquakeStats{
+		Magnitudes: make([]int, len(MagnitudeBounds)),
+		Depths:     make([]int, len(DepthBounds)),
+	}
+}
+
+// v の入る区間。どこにも入らなければ最後（不明）
+func bin[T int | float64](v T, bounds []T) int {
+	for i := 0; i+1 < len(bounds); i++ {
+		if bounds[i] <= v && v < bounds[i+1] {
+			return i
+		}
+	}
+	return len(bounds) - 1
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 訂正で同じ地震の情報が 2 件あるもの、震源・規模が不明なものを含む
+func statsFixtures() []bson.M {
+	return []bson.M{
+		earthquakeDoc{1, "2026/10/01 10:00:00.000", "DetailScale", "2026/10/01 09:58:00", "石川県能登地方", 5.6, 10, 45, []string{"石川県"}}.bson(),
+		earthquakeDoc{2, "2026/10/01 10:30:00.000", "DetailScale", "2026/10/01 09:58:00", "石川県能登地方", 5.7, 10, 50, []string{"石川県", "富山県"}}.bson(),
+		earthquakeDoc{3, "2026/10/01 23:00:00.000", "DetailScale", "2026/10/01 22:58:00", "東京都２３区", 4.1, 30, 30, []string{"東京都"}}.bson(),
+		earthquakeDoc{4, "2026/10/02 10:00:00.000", "DetailScale", "2026/10/02 09:58:00", "京都府南部", 3.2, 10, 10, []string{"京都府"}}.bson(),
+		earthquakeDoc{5, "2026/10/02 11:00:00.000", "DetailScale", "2026/10/02 10:58:00", "", -1, -1, 10, []string{"京都府"}}.bson(),
+		earthquakeDoc{6, "2026/10/03 10:00:00.000", "DetailScale", "2026/10/03 09:58:00", "千島列島", 7.1, 800, 20, []string{"北海道"}}.bson(),
+		earthquakeDoc{7, "2026/10/03 10:01:00.000", "ScalePrompt", "2026/10/03 09:58:00", "", -1, -1, 46, []string{"茨城県"}}.bson(),
+		bson.M{"_id": oid(8), "code": 552, "time": "2026/10/03 10:05:00.000"},
+		bson.M{"_id": oid(9), "code": 556, "time": "2026/10/01 09:58:30.000"},
+		bson.M{"_id": oid(10), "code": 9611, "confidence": 0.95, "started_at": "2026/10/01 09:58:10.000", "time": "2026/10/01 09:58:20.000"},
+		bson.M{"_id": oid(11), "code": 9611, "confidence": 0.96, "started_at": "2026/10/01 09:58:10.000", "time": "2026/10/01 09:58:40.000"},
+		bson.M{"_id": oid(12), "code": 9611, "confidence": 0.3, "started_at": "2026/10/02 09:58:10.000", "time": "2026/10/02 09:58:20.000"},
+	}
+}
+
+var statsCases = []struct {
+	name   string
+	filter EarthquakeFilter
+	page   Page
+}{
+	{name: "detail scale", filter: EarthquakeFilter{IssueTypes: []string{"DetailScale"}}},
+	{name: "period", filter: EarthquakeFilter{IssueTypes: []string{"DetailScale"}}, page: Page{Since: "2026/10/02", Until: "2026/10/03"}},
+	{name: "prefs", filter: EarthquakeFilter{Prefs: []string{"京都府"}}},
+	{name: "hypocenter", filter: EarthquakeFilter{Hypocenter: "能登"}},
+	{name: "all", filter: EarthquakeFilter{}},
+}
+
+func TestMemoryEarthquakeStats(t *testing.T) {
+	m := NewMemory(statsFixtures()...)
+	stats, err := m.EarthquakeStats(context.Background(), EarthquakeFilter{IssueTypes: []string{"DetailScale"}}, Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 能登の地震は訂正後の震度5強のみ数える
+	wantDays := []DayCount{
+		{Day: "2026/10/01", MaxScale: 30, Count: 1},
+		{Day: "2026/10/01", MaxScale: 50, Count: 1},
+		{Day: "2026/10/02", MaxScale: 10, Count: 2},
+		{Day: "2026/10/03", MaxScale: 20, Count: 1},
+	}
+	if !reflect.DeepEqual(stats.Days, wantDays) {
+		t.Errorf("Days = %+v, want %+v", stats.Days, wantDays)
+	}
+	if want := []int{0, 0, 0, 1, 1, 1, 0, 1, 0, 1}; !reflect.DeepEqual(stats.Magnitudes, want) {
+		t.Errorf("Magnitudes = %v, want %v", stats.Magnitudes, want)
+	}
+	if want := []int{0, 2, 0, 1, 0, 0, 0, 0, 2}; !reflect.DeepEqual(stats.Depths, want) {
+		t.Errorf("Depths = %v, want %v", stats.Depths, want)
+	}
+}
+
+// MONGODB_TEST_URL があれば、一時的なコレクションに同じデータを入れて集計結果を比べる
+func TestMongoParity(t *testing.T) {
+	url := os.Getenv("MONGODB_TEST_URL")
+	if url == "" {
+		t.Skip("MONGODB_TEST_URL is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(url))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(context.Background())
+
+	db := client.Database("web_client_test")
+	whole := db.Collection(fmt.Sprintf("whole_%d", time.Now().UnixNano()))
+	defer whole.Drop(context.Background())
+
+	var docs []interface{}
+	for _, item := range statsFixtures() {
+		docs = append(docs, item)
+	}
+	if _, err := whole.InsertMany(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Mongo{Whole: whole, Jma: db.Collection("jma")}
+	memory := NewMemory(statsFixtures()...)
+
+	for _, tt := range statsCases {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := memory.EarthquakeStats(ctx, tt.filter, tt.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := m.EarthquakeStats(ctx, tt.filter, tt.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Mongo EarthquakeStats() = %+v, memory %+v", got, want)
+			}
+		})
+	}
+
+	for _, layout := range []string{"2006/01", "2006/01/02"} {
+		t.Run("archive "+layout, func(t *testing.T) {
+			want, err := memory.CountArchive(ctx, Page{}, len(layout))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := m.CountArchive(ctx, Page{}, len(layout))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(sortCounts(got), sortCounts(want)) {
+				t.Errorf("Mongo CountArchive() = %+v, memory %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 変更ストリームで追加されたドキュメントを流す。エラー時は再開トークンから開き直す
+func (m *Mongo) Watch(ctx context.Context) (<-chan bson.M, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"operationType": "insert",
+			"$or": bson.A{
+				bson.M{"fullDocument.code": bson.M{"$in": jmaCodes}},
+				bson.M{
+					"fullDocument.code":       userquakeCode,
+					"fullDocument.confidence": bson.M{"$gt": userquakeMinConfidence},
+				},
+			},
+		}}},
+	}
+
+	cs, err := m.Whole.Watch(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan bson.M)
+	go func() {
+		defer close(ch)
+		var token bson.Raw
+		for {
+			for cs.Next(ctx) {
+				token = cs.ResumeToken()
+
+				var event struct {
+					FullDocument bson.M `bson:"fullDocument"`
+				}
+				if err := cs.Decode(&event); err != nil {
+					log.Printf("Change stream decode error: %v\n", err)
+					continue
+				}
+				select {
+				case ch <- event.FullDocument:
+				case <-ctx.Done():
+				}
+			}
+			if err := cs.Err(); err != nil && ctx.Err() == nil {
+				log.Printf("Change stream error: %v\n", err)
+			}
+			cs.Close(context.Background())
+
+			// 開き直す
+			for {
+				if ctx.Err() != nil {
+					return
+				}
+				opts := options.ChangeStream()
+				if token != nil {
+					opts.SetResumeAfter(token)
+				}
+				cs, err = m.Whole.Watch(ctx, pipeline, opts)
+				if err == nil {
+					break
+				}
+				log.Printf("Change stream reopen error: %v\n", err)
+				select {
+				case <-time.After(5 * time.Second):
+				case <-ctx.Done():
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+func (m *Memory) Watch(ctx context.Context) (<-chan bson.M, error) {
+	ch := make(chan bson.M, 16)
+
+	m.mu.Lock()
+	m.watchers = append(m.watchers, ch)
+	m.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		// 追いつけずに Insert で閉じられたものは残っていない
+		for i, w := range m.watchers {
+			if w == ch {
+				m.watchers = append(m.watchers[:i], m.watchers[i+1:]...)
+				close(ch)
+				break
+			}
+		}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMemoryWatch(t *testing.T) {
+	m := NewMemory()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := m.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.Insert(bson.M{"_id": oid(1), "code": 551, "time": "2026/10/17 10:00:00.000"})
+	m.Insert(bson.M{"_id": oid(2), "code": 9611, "confidence": 0.5, "time": "2026/10/17 10:00:01.000"})
+	m.Insert(bson.M{"_id": oid(3), "code": 552, "time": "2026/10/17 10:00:02.000"})
+	if got := ids([]bson.M{<-ch, <-ch}); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("Watch() = %v, want [1 3]", got)
+	}
+
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Error("channel is not closed after ctx is done")
+	}
+}
+
+// 受け取りが追いつかなければ閉じて、取りこぼしを知らせる
+func TestMemoryWatchOverflow(t *testing.T) {
+	m := NewMemory()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := m.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := cap(ch) + 1
+	for i := 0; i < n; i++ {
+		m.Insert(bson.M{"code": 551, "time": "2026/10/17 10:00:00.000"})
+	}
+
+	received := 0
+	for range ch {
+		received++
+	}
+	if received != n-1 {
+		t.Errorf("received %d items before closed, want %d", received, n-1)
+	}
+}
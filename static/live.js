@@ -0,0 +1,56 @@
+(function () {
+  const cards = document.getElementById('cards');
+  if (!cards || !cards.dataset.live || !window.EventSource) return;
+
+  const currentTime = document.getElementById('current-time');
+  const source = new EventSource(cards.dataset.live);
+
+  ['earthquake', 'tsunami', 'eew', 'userquake'].forEach(type => {
+    source.addEventListener(type, e => {
+      try {
+        insertCard(JSON.parse(e.data));
+      } catch (error) {
+        console.error('Error handling live event:', error);
+      }
+      updateCurrentTime();
+    });
+  });
+
+  // 取りこぼしがあり再送できない場合。一覧ごと読み込み直す
+  source.addEventListener('reset', () => {
+    source.close();
+    location.reload();
+  });
+
+  source.addEventListener('heartbeat', e => {
+    if (currentTime) currentTime.textContent = e.data;
+  });
+
+  function insertCard(payload) {
+    const template = document.createElement('template');
+    template.innerHTML = payload.html.trim();
+    const card = template.content.firstElementChild;
+    if (!card) return;
+
+    // 同じ地震感知情報（started_at）は新しいもので置き換える
+    const existing = cards.querySelector(`[data-card-key="${CSS.escape(payload.key)}"]`);
+    if (existing) {
+      existing.replaceWith(card);
+    } else {
+      const next = Array.from(cards.children).find(c => (c.dataset.cardTime || '') < payload.time);
+      cards.insertBefore(card, next || null);
+    }
+
+    // innerHTML で挿入したスクリプトは実行されないため、ここで初期化する
+    card.querySelectorAll('[data-userquake-id]').forEach(el => {
+      if (window.initUserquakeTimeline) initUserquakeTimeline(el.dataset.userquakeId);
+    });
+  }
+
+  function updateCurrentTime() {
+    if (!currentTime) return;
+    const now = new Date();
+    const pad = n => String(n).padStart(2, '0');
+    currentTime.textContent = `${pad(now.getMonth() + 1)}/${pad(now.getDate())} ${pad(now.getHours())}:${pad(now.getMinutes())}:${pad(now.getSeconds())}`;
+  }
+})();
@@ -1,3 +1,6 @@
+// サブパスに置いても動くよう、API や地図の URL はこのスクリプトの位置（{root}static/）から求める
+const siteRoot = new URL('../', document.currentScript.src);
+
 function initUserquakeTimeline(objectId) {
   const userquakeContainer = document.querySelector(`[data-userquake-id="${objectId}"]`);
   if (!userquakeContainer) return;
@@ -9,6 +12,7 @@ function initUserquakeTimeline(objectId) {
   const confidenceDisplay = userquakeContainer.querySelector('.timeline-confidence-display');
   const timelineImage = userquakeContainer.querySelector('.timeline-image');
   const timelineImageLink = userquakeContainer.querySelector('.timeline-image-link');
+  const localMap = userquakeContainer.dataset.localMap === 'true';
 
   let timeseriesData = [];
   let isPlaying = false;
@@ -21,7 +25,7 @@ function initUserquakeTimeline(objectId) {
   let isPreloading = false;
   let hasPreloaded = false;
 
-  fetch(`./api/timeseries/${objectId}`)
+  fetch(new URL(`api/timeseries/${objectId}`, siteRoot))
     .then(response => response.json())
     .then(data => {
       timeseriesData = data;
@@ -202,7 +206,7 @@ function initUserquakeTimeline(objectId) {
         const objectId = extractObjectId(data);
         
         if (objectId) {
-          const imageUrl = `https://cdn.p2pquake.net/app/web/userquake?id=${objectId}&suffix=_trim`;
+          const imageUrl = mapUrl(objectId);
           const img = new Image();
           
           img.onload = () => {
@@ -231,6 +235,14 @@ function initUserquakeTimeline(objectId) {
     });
   }
 
+  // 各コマの地図（サーバで描いたもの、または CDN の画像）
+  function mapUrl(objectId) {
+    if (localMap) {
+      return new URL(`map/userquake/${objectId}.svg`, siteRoot).href;
+    }
+    return `https://cdn.p2pquake.net/app/web/userquake?id=${objectId}&suffix=_trim`;
+  }
+
   function extractObjectId(data) {
     if (data._id) {
       if (typeof data._id === 'string') {
@@ -254,7 +266,7 @@ function initUserquakeTimeline(objectId) {
         timelineImageLink.href = preloadedImg.src;
       } else {
         console.warn('Preloaded image not found for objectId:', objectId, 'Available keys:', Array.from(preloadedImages.keys()));
-        const imageUrl = `https://cdn.p2pquake.net/app/web/userquake?id=${objectId}&suffix=_trim`;
+        const imageUrl = mapUrl(objectId);
         timelineImage.src = imageUrl;
         timelineImageLink.href = imageUrl;
       }
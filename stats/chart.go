@@ -0,0 +1,180 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+
+	"github.com/p2pquake/web-client/svgmap"
+)
+
+const (
+	chartWidth   = 800
+	chartHeight  = 320
+	chartLeft    = 48
+	chartRight   = 16
+	chartTop     = 56
+	chartBottom  = 40
+	chartLabels  = 8 // 横軸の目盛りの数の目安（年を含む日付が重ならない程度）
+	unknownColor = "#C0C0C0"
+	binColor     = "#4A7FC0"
+	// 「5弱以上と推定」は 5弱と同じ色だと区別できないので淡くする
+	estimatedColor = "#C0E080"
+)
+
+type chart struct {
+	buf     bytes.Buffer
+	max     float64 // 縦軸の最大値
+	step    float64 // 縦軸の目盛りの間隔
+	barSize float64
+}
+
+func newChart(title string, n, top int) *chart {
+	c := &chart{}
+	c.step = niceStep(top)
+	c.max = math.Max(c.step, math.Ceil(float64(top)/c.step)*c.step)
+	if n > 0 {
+		c.barSize = float64(chartWidth-chartLeft-chartRight) / float64(n)
+	}
+
+	fmt.Fprintf(&c.buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="sans-serif">`, chartWidth, chartHeight, chartWidth, chartHeight)
+	c.buf.WriteString("<title>")
+	xml.EscapeText(&c.buf, []byte(title))
+	c.buf.WriteString("</title>")
+	fmt.Fprintf(&c.buf, `<rect width="%d" height="%d" fill="#FFFFFF"/>`, chartWidth, chartHeight)
+	c.text(chartWidth/2, 20, title, 14, "middle")
+
+	// 縦軸の目盛りと補助線
+	for v := 0.0; v <= c.max; v += c.step {
+		y := c.y(v)
+		fmt.Fprintf(&c.buf, `<path d="M%d %.1fH%d" stroke="#E0E0E0"/>`, chartLeft, y, chartWidth-chartRight)
+		c.text(chartLeft-4, y+4, fmt.Sprintf("%.0f", v), 11, "end")
+	}
+	fmt.Fprintf(&c.buf, `<path d="M%d %dH%d" stroke="#808080"/>`, chartLeft, chartHeight-chartBottom, chartWidth-chartRight)
+	return c
+}
+
+// 1, 2, 5 の 10 のべき乗倍のうち、目盛りが 5 つ程度になる間隔
+func niceStep(top int) float64 {
+	if top <= 0 {
+		return 1
+	}
+	for scale := 1.0; ; scale *= 10 {
+		for _, s := range []float64{1, 2, 5} {
+			if float64(top)/(s*scale) <= 5 {
+				return s * scale
+			}
+		}
+	}
+}
+
+func (c *chart) y(v float64) float64 {
+	h := float64(chartHeight - chartTop - chartBottom)
+	return float64(chartHeight-chartBottom) - v/c.max*h
+}
+
+// i 番目の棒の from から to までを塗る
+func (c *chart) bar(i int, from, to float64, fill string) {
+	if to <= from {
+		return
+	}
+	x := float64(chartLeft) + c.barSize*float64(i) + c.barSize*0.1
+	fmt.Fprintf(&c.buf, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`, x, c.y(to), c.barSize*0.8, c.y(from)-c.y(to), fill)
+}
+
+// 横軸の目盛り。棒が多い場合は間引く
+func (c *chart) labels(labels []string) {
+	every := (len(labels) + chartLabels - 1) / chartLabels
+	for i, label := range labels {
+		if every > 1 && i%every != 0 {
+			continue
+		}
+		x := float64(chartLeft) + c.barSize*(float64(i)+0.5)
+		c.text(x, chartHeight-chartBottom+16, label, 11, "middle")
+	}
+}
+
+// 凡例の四角形と名前
+func (c *chart) legend(x, y float64, name, fill string) {
+	fmt.Fprintf(&c.buf, `<rect x="%.1f" y="%.1f" width="10" height="10" fill="%s"/>`, x, y-9, fill)
+	c.text(x+13, y, name, 11, "start")
+}
+
+func (c *chart) text(x, y float64, text string, size int, anchor string) {
+	fmt.Fprintf(&c.buf, `<text x="%.1f" y="%.1f" font-size="%d" fill="#000000" text-anchor="%s">`, x, y, size, anchor)
+	xml.EscapeText(&c.buf, []byte(text))
+	c.buf.WriteString("</text>")
+}
+
+func (c *chart) bytes() []byte {
+	c.buf.WriteString("</svg>")
+	return c.buf.Bytes()
+}
+
+// 期間ごとの件数を最大震度で積み上げた棒グラフ
+func ScaleChart(s *Summary) []byte {
+	top := 0
+	var labels []string
+	for _, p := range s.Periods {
+		top = max(top, p.Total)
+		labels = append(labels, p.Label)
+	}
+
+	c := newChart("最大震度別の地震の回数", len(s.Periods), top)
+	for i, p := range s.Periods {
+		sum := 0
+		for j, count := range p.Counts {
+			c.bar(i, float64(sum), float64(sum+count), scaleFill(s.Scales[j].Code))
+			sum += count
+		}
+	}
+	c.labels(labels)
+
+	// 凡例（「5弱以上と推定」は長いので「推定」とする）
+	x := float64(chartLeft)
+	for _, scale := range s.Scales {
+		name := scale.Name
+		if scale.Code == 46 {
+			name = "推定"
+		}
+		c.legend(x, 42, name, scaleFill(scale.Code))
+		x += 18 + float64(len([]rune(name)))*11
+	}
+	return c.bytes()
+}
+
+// 度数分布の棒グラフ
+func HistogramChart(title string, bins []Bin) []byte {
+	top := 0
+	var labels []string
+	for _, b := range bins {
+		top = max(top, b.Count)
+		labels = append(labels, b.Label)
+	}
+
+	c := newChart(title, len(bins), top)
+	for i, b := range bins {
+		fill := binColor
+		if i == len(bins)-1 {
+			fill = unknownColor
+		}
+		c.bar(i, 0, float64(b.Count), fill)
+		if b.Count > 0 {
+			x := float64(chartLeft) + c.barSize*(float64(i)+0.5)
+			c.text(x, c.y(float64(b.Count))-4, fmt.Sprint(b.Count), 11, "middle")
+		}
+	}
+	c.labels(labels)
+	return c.bytes()
+}
+
+func scaleFill(code int) string {
+	switch {
+	case code < 0:
+		return unknownColor
+	case code == 46:
+		return estimatedColor
+	}
+	return svgmap.ScaleColor(code)
+}
@@ -0,0 +1,150 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/repository"
+)
+
+// 集計の単位
+const (
+	Day   = "day"
+	Week  = "week"
+	Month = "month"
+)
+
+// 期間の数の上限（グラフの棒の数）
+const MaxPeriods = 400
+
+var (
+	ErrInvalidInterval = errors.New("invalid interval")
+	ErrTooManyPeriods  = errors.New("too many periods")
+)
+
+// 最大震度の分類（model.ScaleName と同じ。小さい順、最後は不明）
+var scaleClasses = []int{10, 20, 30, 40, 46, 45, 50, 55, 60, 70, -1}
+
+type Summary struct {
+	From       string   `json:"from"` // 2006-01-02
+	To         string   `json:"to"`
+	Interval   string   `json:"interval"`
+	Scales     []Scale  `json:"scales"`
+	Periods    []Period `json:"periods"` // 古い順
+	Magnitudes []Bin    `json:"magnitudes"`
+	Depths     []Bin    `json:"depths"`
+	Total      int      `json:"total"`
+}
+
+type Scale struct {
+	Code int    `json:"code"` // 不明は -1
+	Name string `json:"name"`
+}
+
+type Period struct {
+	Start  string `json:"start"` // 2006/01/02
+	Label  string `json:"label"`
+	Counts []int  `json:"counts"` // Scales と同じ順
+	Total  int    `json:"total"`
+}
+
+type Bin struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// from の日から to の日までを interval ごとに区切ったときの各期間の始まり
+func PeriodStarts(from, to time.Time, interval string) ([]time.Time, error) {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	var next func(time.Time) time.Time
+	switch interval {
+	case Day:
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case Week:
+		// 月曜始まり
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case Month:
+		start = start.AddDate(0, 0, 1-start.Day())
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	default:
+		return nil, ErrInvalidInterval
+	}
+
+	var starts []time.Time
+	for t := start; !t.After(to); t = next(t) {
+		if len(starts) == MaxPeriods {
+			return nil, ErrTooManyPeriods
+		}
+		starts = append(starts, t)
+	}
+	return starts, nil
+}
+
+// 日ごとの件数を starts の期間ごとにまとめる
+func Summarize(s *repository.EarthquakeStats, starts []time.Time, interval string) *Summary {
+	summary := &Summary{Interval: interval}
+	classIndex := make(map[int]int)
+	for i, code := range scaleClasses {
+		summary.Scales = append(summary.Scales, Scale{Code: code, Name: model.ScaleName(code)})
+		classIndex[code] = i
+	}
+
+	for _, t := range starts {
+		summary.Periods = append(summary.Periods, Period{
+			Start:  t.Format("2006/01/02"),
+			Label:  periodLabel(t, interval),
+			Counts: make([]int, len(scaleClasses)),
+		})
+	}
+
+	for _, d := range s.Days {
+		day, err := time.ParseInLocation("2006/01/02", d.Day, starts[0].Location())
+		if err != nil {
+			continue
+		}
+		i := sort.Search(len(starts), func(i int) bool { return starts[i].After(day) }) - 1
+		if i < 0 {
+			continue
+		}
+
+		class, ok := classIndex[d.MaxScale]
+		if !ok {
+			class = len(scaleClasses) - 1
+		}
+		p := &summary.Periods[i]
+		p.Counts[class] += d.Count
+		p.Total += d.Count
+		summary.Total += d.Count
+	}
+
+	for i, count := range s.Magnitudes {
+		label := "不明"
+		if i+1 < len(repository.MagnitudeBounds) {
+			label = fmt.Sprintf("M%.0f〜", repository.MagnitudeBounds[i])
+		}
+		summary.Magnitudes = append(summary.Magnitudes, Bin{Label: label, Count: count})
+	}
+	for i, count := range s.Depths {
+		label := "不明"
+		if i+1 < len(repository.DepthBounds) {
+			label = fmt.Sprintf("%dkm〜", repository.DepthBounds[i])
+		}
+		summary.Depths = append(summary.Depths, Bin{Label: label, Count: count})
+	}
+
+	return summary
+}
+
+func periodLabel(t time.Time, interval string) string {
+	switch interval {
+	case Week:
+		return t.Format("2006/01/02") + "〜"
+	case Month:
+		return t.Format("2006/01")
+	}
+	return t.Format("2006/01/02")
+}
@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/repository"
+)
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", s, model.JST)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestPeriodStarts(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		interval string
+		want     []string
+		err      error
+	}{
+		{name: "day", from: "2026-09-29", to: "2026-10-02", interval: Day, want: []string{"2026-09-29", "2026-09-30", "2026-10-01", "2026-10-02"}},
+		{name: "single day", from: "2026-10-01", to: "2026-10-01", interval: Day, want: []string{"2026-10-01"}},
+		// 2026-10-01 は木曜日。月曜始まり
+		{name: "week", from: "2026-10-01", to: "2026-10-12", interval: Week, want: []string{"2026-09-28", "2026-10-05", "2026-10-12"}},
+		{name: "week from sunday", from: "2026-10-04", to: "2026-10-04", interval: Week, want: []string{"2026-09-28"}},
+		{name: "month", from: "2025-12-15", to: "2026-02-01", interval: Month, want: []string{"2025-12-01", "2026-01-01", "2026-02-01"}},
+		{name: "invalid interval", from: "2026-10-01", to: "2026-10-02", interval: "year", err: ErrInvalidInterval},
+		{name: "too many periods", from: "2020-01-01", to: "2026-01-01", interval: Day, err: ErrTooManyPeriods},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starts, err := PeriodStarts(date(tt.from), date(tt.to), tt.interval)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("PeriodStarts() error = %v, want %v", err, tt.err)
+			}
+			var got []string
+			for _, s := range starts {
+				got = append(got, s.Format("2006-01-02"))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PeriodStarts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	raw := &repository.EarthquakeStats{
+		Days: []repository.DayCount{
+			{Day: "2026/09/27", MaxScale: 10, Count: 9}, // 期間より前
+			{Day: "2026/09/28", MaxScale: 10, Count: 3},
+			{Day: "2026/09/30", MaxScale: 46, Count: 1},
+			{Day: "2026/10/05", MaxScale: 45, Count: 2},
+			{Day: "2026/10/06", MaxScale: -1, Count: 1},
+			{Day: "2026/10/07", MaxScale: 99, Count: 1}, // 分類にない震度は不明
+		},
+		Magnitudes: []int{0, 0, 1, 2, 3, 1, 0, 0, 0, 1},
+		Depths:     []int{4, 1, 0, 0, 1, 0, 0, 0, 2},
+	}
+	starts, err := PeriodStarts(date("2026-10-01"), date("2026-10-07"), Week)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := Summarize(raw, starts, Week)
+
+	if len(s.Scales) != len(scaleClasses) || s.Scales[4].Name != "5弱以上と推定" || s.Scales[len(s.Scales)-1].Code != -1 {
+		t.Errorf("Scales = %+v", s.Scales)
+	}
+
+	counts := func(pairs map[int]int) []int {
+		c := make([]int, len(scaleClasses))
+		for code, n := range pairs {
+			for i, class := range scaleClasses {
+				if class == code {
+					c[i] = n
+				}
+			}
+		}
+		return c
+	}
+	want := []Period{
+		{Start: "2026/09/28", Label: "2026/09/28〜", Counts: counts(map[int]int{10: 3, 46: 1}), Total: 4},
+		{Start: "2026/10/05", Label: "2026/10/05〜", Counts: counts(map[int]int{45: 2, -1: 2}), Total: 4},
+	}
+	if !reflect.DeepEqual(s.Periods, want) {
+		t.Errorf("Periods = %+v, want %+v", s.Periods, want)
+	}
+	if s.Total != 8 {
+		t.Errorf("Total = %d, want 8", s.Total)
+	}
+
+	if len(s.Magnitudes) != len(raw.Magnitudes) || s.Magnitudes[4] != (Bin{Label: "M4〜", Count: 3}) || s.Magnitudes[len(s.Magnitudes)-1] != (Bin{Label: "不明", Count: 1}) {
+		t.Errorf("Magnitudes = %+v", s.Magnitudes)
+	}
+	if len(s.Depths) != len(raw.Depths) || s.Depths[4] != (Bin{Label: "50km〜", Count: 1}) || s.Depths[len(s.Depths)-1] != (Bin{Label: "不明", Count: 2}) {
+		t.Errorf("Depths = %+v", s.Depths)
+	}
+}
+
+func TestPeriodLabel(t *testing.T) {
+	tests := []struct {
+		interval string
+		want     string
+	}{
+		{Day, "2026/10/05"},
+		{Week, "2026/10/05〜"},
+		{Month, "2026/10"},
+	}
+	for _, tt := range tests {
+		if got := periodLabel(date("2026-10-05"), tt.interval); got != tt.want {
+			t.Errorf("periodLabel(%s) = %q, want %q", tt.interval, got, tt.want)
+		}
+	}
+}
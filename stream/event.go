@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/p2pquake/web-client/model"
+	"github.com/p2pquake/web-client/renderer"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Event struct {
+	ID   string // ObjectID
+	Type string
+	Data []byte
+}
+
+type payload struct {
+	ID   string      `json:"id"`
+	Code int         `json:"code"`
+	Time string      `json:"time"`
+	Key  string      `json:"key"`  // 一覧で差し替える単位
+	HTML string      `json:"html"` // 一覧に挿入するカード
+	Data interface{} `json:"data"`
+}
+
+func NewEvent(item bson.M) (Event, error) {
+	id, ok := item["_id"].(primitive.ObjectID)
+	if !ok {
+		return Event{}, fmt.Errorf("no _id: %v", item["_id"])
+	}
+
+	code := model.ToInt(item["code"])
+	t, _ := item["time"].(string)
+	if code == 9611 {
+		// findUserquakes と同じく started_at を時刻とする
+		t, _ = item["started_at"].(string)
+	}
+
+	card, err := renderer.ToCard(item)
+	if err != nil {
+		return Event{}, err
+	}
+	html, err := renderer.RenderFragment(item)
+	if err != nil {
+		return Event{}, err
+	}
+
+	b, err := json.Marshal(payload{ID: id.Hex(), Code: code, Time: t, Key: card.Key, HTML: html, Data: card.Data})
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{ID: id.Hex(), Type: eventType(code), Data: b}, nil
+}
+
+func (e Event) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	if e.ID != "" {
+		fmt.Fprintf(&sb, "id: %s\n", e.ID)
+	}
+	fmt.Fprintf(&sb, "event: %s\n", e.Type)
+	for _, line := range strings.Split(string(e.Data), "\n") {
+		fmt.Fprintf(&sb, "data: %s\n", line)
+	}
+	sb.WriteString("\n")
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// 接続維持と「現在」時刻の更新を兼ねる
+func Heartbeat(now time.Time) Event {
+	return Event{Type: "heartbeat", Data: []byte(now.Format("01/02 15:04:05"))}
+}
+
+// 再送できないイベントがあるため、一覧を読み込み直させる
+func Reset() Event {
+	return Event{Type: "reset", Data: []byte("reload")}
+}
+
+func eventType(code int) string {
+	switch code {
+	case 551:
+		return "earthquake"
+	case 552:
+		return "tsunami"
+	case 556:
+		return "eew"
+	case 9611:
+		return "userquake"
+	}
+	return "unknown"
+}
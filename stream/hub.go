@@ -0,0 +1,141 @@
+package stream
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Last-Event-ID で再送するために保持する件数
+	recentSize = 256
+	// クライアントごとの送信待ちの上限（超えたら切断して再接続させる）
+	clientBuffer = 32
+)
+
+type Watcher interface {
+	Watch(ctx context.Context) (<-chan bson.M, error)
+}
+
+// 1 つの変更ストリームを全クライアントに配る
+type Hub struct {
+	watcher Watcher
+
+	mu      sync.Mutex
+	clients map[chan Event]struct{}
+	recent  []Event
+	since   string // この ID より後のイベントはすべて recent にある
+}
+
+func NewHub(watcher Watcher) *Hub {
+	return &Hub{
+		watcher: watcher,
+		clients: make(map[chan Event]struct{}),
+		since:   primitive.NewObjectIDFromTimestamp(time.Now()).Hex(),
+	}
+}
+
+// ctx が終わるまで変更ストリームを読み続ける
+func (h *Hub) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		ch, err := h.watcher.Watch(ctx)
+		if err != nil {
+			log.Printf("Watch error: %v\n", err)
+			select {
+			case <-time.After(5 * time.Second):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		for item := range ch {
+			event, err := NewEvent(item)
+			if err != nil {
+				log.Printf("Event error: %v\n", err)
+				continue
+			}
+			h.Publish(event)
+		}
+		if ctx.Err() == nil {
+			// 変更ストリームが途切れた間のイベントは分からない
+			log.Printf("Watch closed, resetting clients\n")
+			h.Reset()
+		}
+	}
+}
+
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.recent = append(h.recent, event)
+	if len(h.recent) > recentSize {
+		h.since = h.recent[len(h.recent)-recentSize-1].ID
+		h.recent = h.recent[len(h.recent)-recentSize:]
+	}
+
+	h.broadcast(event)
+}
+
+// 取りこぼした可能性があるため、保持済みのイベントを捨て、接続中のクライアントに読み込み直させる
+func (h *Hub) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.recent = nil
+	h.since = primitive.NewObjectIDFromTimestamp(time.Now()).Hex()
+	h.broadcast(Reset())
+}
+
+func (h *Hub) broadcast(event Event) {
+	for c := range h.clients {
+		select {
+		case c <- event:
+		default:
+			// 追いつけないクライアントは切断する。再接続時に Last-Event-ID から再送する
+			delete(h.clients, c)
+			close(c)
+		}
+	}
+}
+
+// lastEventID より後の保持済みイベントと、以降のイベントを受け取るチャネルを返す。
+// lastEventID が保持している範囲より古ければ、再送の代わりに Reset を返す。
+// チャネルは cancel を呼ぶか、受信が追いつかなくなると閉じられる
+func (h *Hub) Subscribe(lastEventID string) (replay []Event, events <-chan Event, cancel func()) {
+	c := make(chan Event, clientBuffer)
+
+	h.mu.Lock()
+	// ObjectID は生成時刻順なので 16 進文字列で比較できる
+	if lastEventID != "" && lastEventID < h.since {
+		replay = []Event{Reset()}
+	} else if lastEventID != "" {
+		for _, e := range h.recent {
+			if e.ID > lastEventID {
+				replay = append(replay, e)
+			}
+		}
+	}
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+
+	cancel = func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.clients[c]; ok {
+			delete(h.clients, c)
+			close(c)
+		}
+	}
+	return replay, c, cancel
+}
+
+func (h *Hub) Clients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
@@ -0,0 +1,176 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newEvents(n int) []Event {
+	events := make([]Event, n)
+	for i := range events {
+		events[i] = Event{ID: primitive.NewObjectID().Hex(), Type: "earthquake"}
+	}
+	return events
+}
+
+func ids(events []Event) []string {
+	var result []string
+	for _, e := range events {
+		result = append(result, e.ID)
+	}
+	return result
+}
+
+func TestHubPublish(t *testing.T) {
+	h := NewHub(nil)
+	replay, events, cancel := h.Subscribe("")
+	if len(replay) != 0 {
+		t.Errorf("replay = %v, want none", replay)
+	}
+
+	e := newEvents(2)
+	h.Publish(e[0])
+	h.Publish(e[1])
+	for i := range e {
+		if got := <-events; got.ID != e[i].ID {
+			t.Errorf("event %d = %s, want %s", i, got.ID, e[i].ID)
+		}
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Error("events is not closed after cancel")
+	}
+	if h.Clients() != 0 {
+		t.Errorf("Clients() = %d, want 0", h.Clients())
+	}
+	cancel() // 二度呼んでもよい
+}
+
+func TestHubReplay(t *testing.T) {
+	h := NewHub(nil)
+	e := newEvents(recentSize + 2)
+	for _, event := range e[:3] {
+		h.Publish(event)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        []string
+	}{
+		{name: "no id"},
+		{name: "after first", lastEventID: e[0].ID, want: ids(e[1:3])},
+		{name: "latest", lastEventID: e[2].ID},
+		{name: "before the hub started", lastEventID: primitive.NewObjectIDFromTimestamp(time.Now().Add(-time.Hour)).Hex(), want: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, _, cancel := h.Subscribe(tt.lastEventID)
+			defer cancel()
+			if got := ids(replay); !equal(got, tt.want) {
+				t.Errorf("replay = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 保持する件数を超えて捨てたものより前からは再送できない
+	for _, event := range e[3:] {
+		h.Publish(event)
+	}
+	replay, _, cancel := h.Subscribe(e[0].ID)
+	defer cancel()
+	if len(replay) != 1 || replay[0].Type != "reset" {
+		t.Errorf("replay from before a dropped event has %d events, want reset", len(replay))
+	}
+	replay, _, cancel2 := h.Subscribe(e[1].ID)
+	defer cancel2()
+	if got := ids(replay); !equal(got, ids(e[2:])) {
+		t.Errorf("replay from the last dropped event has %d events, want %d", len(got), len(e)-2)
+	}
+}
+
+func TestHubDropsSlowClients(t *testing.T) {
+	h := NewHub(nil)
+	_, slow, cancel := h.Subscribe("")
+	defer cancel()
+	_, fast, cancel2 := h.Subscribe("")
+	defer cancel2()
+
+	for _, e := range newEvents(clientBuffer + 1) {
+		h.Publish(e)
+		<-fast
+	}
+
+	n := 0
+	for range slow {
+		n++
+	}
+	if n != clientBuffer {
+		t.Errorf("slow client received %d events before closed, want %d", n, clientBuffer)
+	}
+	if h.Clients() != 1 {
+		t.Errorf("Clients() = %d, want 1", h.Clients())
+	}
+}
+
+type fakeWatcher struct {
+	chans chan chan bson.M
+}
+
+func (w *fakeWatcher) Watch(ctx context.Context) (<-chan bson.M, error) {
+	select {
+	case ch := <-w.chans:
+		return ch, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestHubResetsWhenWatchCloses(t *testing.T) {
+	w := &fakeWatcher{chans: make(chan chan bson.M, 1)}
+	h := NewHub(w)
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	h.Publish(newEvents(1)[0])
+	before := h.recent[0].ID
+	_, events, cancel := h.Subscribe("")
+	defer cancel()
+
+	ch := make(chan bson.M)
+	w.chans <- ch
+	go h.Run(ctx)
+	close(ch)
+
+	select {
+	case e := <-events:
+		if e.Type != "reset" {
+			t.Errorf("event = %+v, want reset", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no reset after the watch closed")
+	}
+
+	replay, _, cancel2 := h.Subscribe(before)
+	defer cancel2()
+	if len(replay) != 1 || replay[0].Type != "reset" {
+		t.Errorf("replay across the gap = %v, want reset", replay)
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package svgmap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const (
+	seaColor   = "#DCEBF5"
+	landColor  = "#F4F1EA"
+	coastColor = "#8A8A8A"
+)
+
+type canvas struct {
+	buf  bytes.Buffer
+	proj projection
+}
+
+// 海と陸地を描いたところから始める
+func newCanvas(bounds Bounds, width, height float64, title string) *canvas {
+	c := &canvas{proj: newProjection(bounds, width, height)}
+	fmt.Fprintf(&c.buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" width="%.0f" height="%.0f" font-family="sans-serif">`, width, height, width, height)
+	c.buf.WriteString("<title>")
+	xml.EscapeText(&c.buf, []byte(title))
+	c.buf.WriteString("</title>")
+	fmt.Fprintf(&c.buf, `<rect width="%.0f" height="%.0f" fill="%s"/>`, width, height, seaColor)
+
+	c.buf.WriteString(`<g fill="` + landColor + `" stroke="` + coastColor + `" stroke-width="0.8" stroke-linejoin="round">`)
+	c.land()
+	c.buf.WriteString("</g>")
+	return c
+}
+
+func (c *canvas) land() {
+	for _, r := range coastline {
+		if r.bounds.intersects(c.proj.bounds) {
+			c.polygon(r.Coordinates, "")
+		}
+	}
+}
+
+// 陸地で切り抜くグループを始める。endLand で閉じる
+func (c *canvas) beginLand(attrs string) {
+	c.buf.WriteString(`<clipPath id="land">`)
+	c.land()
+	c.buf.WriteString(`</clipPath><g clip-path="url(#land)" ` + attrs + `>`)
+}
+
+// 切り抜いた地域の上に海岸線を描き直す
+func (c *canvas) endLand() {
+	c.buf.WriteString(`</g><g fill="none" stroke="` + coastColor + `" stroke-width="0.8" stroke-linejoin="round">`)
+	c.land()
+	c.buf.WriteString("</g>")
+}
+
+// coordinates は [経度, 緯度] の並び。attrs は追加の属性
+func (c *canvas) polygon(coordinates [][2]float64, attrs string) {
+	c.buf.WriteString(`<path d="`)
+	c.buf.WriteString(c.pathData(coordinates, true))
+	c.buf.WriteString(`"`)
+	if attrs != "" {
+		c.buf.WriteString(" " + attrs)
+	}
+	c.buf.WriteString("/>")
+}
+
+func (c *canvas) polyline(coordinates [][2]float64, attrs string) {
+	c.buf.WriteString(`<path fill="none" d="`)
+	c.buf.WriteString(c.pathData(coordinates, false))
+	c.buf.WriteString(`"`)
+	if attrs != "" {
+		c.buf.WriteString(" " + attrs)
+	}
+	c.buf.WriteString("/>")
+}
+
+func (c *canvas) pathData(coordinates [][2]float64, closed bool) string {
+	var d strings.Builder
+	for i, p := range coordinates {
+		x, y := c.proj.point(p[1], p[0])
+		if i == 0 {
+			fmt.Fprintf(&d, "M%.1f %.1f", x, y)
+		} else {
+			fmt.Fprintf(&d, "L%.1f %.1f", x, y)
+		}
+	}
+	if closed {
+		d.WriteString("Z")
+	}
+	return d.String()
+}
+
+// 震源を表す ×
+func (c *canvas) cross(latitude, longitude, size float64) {
+	x, y := c.proj.point(latitude, longitude)
+	d := fmt.Sprintf("M%.1f %.1fL%.1f %.1fM%.1f %.1fL%.1f %.1f", x-size, y-size, x+size, y+size, x-size, y+size, x+size, y-size)
+	fmt.Fprintf(&c.buf, `<path d="%s" stroke="#FFFFFF" stroke-width="7" stroke-linecap="round"/>`, d)
+	fmt.Fprintf(&c.buf, `<path d="%s" stroke="#E00000" stroke-width="4" stroke-linecap="round"/>`, d)
+}
+
+// 震度などを書いた四角形
+func (c *canvas) label(latitude, longitude float64, text, fill, color string) {
+	x, y := c.proj.point(latitude, longitude)
+	c.box(x, y, text, fill, color)
+}
+
+func (c *canvas) box(x, y float64, text, fill, color string) {
+	const size = 18
+	fmt.Fprintf(&c.buf, `<rect x="%.1f" y="%.1f" width="%d" height="%d" rx="2" fill="%s" stroke="#000000" stroke-width="0.8"/>`, x-size/2, y-size/2, size, size, fill)
+	c.text(x, y+4.5, text, color, 12, "middle")
+}
+
+func (c *canvas) text(x, y float64, text, color string, size int, anchor string) {
+	fmt.Fprintf(&c.buf, `<text x="%.1f" y="%.1f" font-size="%d" font-weight="bold" fill="%s" text-anchor="%s">`, x, y, size, color, anchor)
+	xml.EscapeText(&c.buf, []byte(text))
+	c.buf.WriteString("</text>")
+}
+
+func (c *canvas) bytes() []byte {
+	c.buf.WriteString("</svg>")
+	return c.buf.Bytes()
+}
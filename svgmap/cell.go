@@ -0,0 +1,77 @@
+package svgmap
+
+import "math"
+
+// 地域の形を代表点のボロノイ領域で近似するときの、経度方向の縮尺（日本の中ほどの緯度）
+var cellCos = math.Cos(36 * math.Pi / 180)
+
+// 代表点からこの倍数×半径より遠くは、どの地域にも含めない（離島が海を越えて広がらないように）
+const cellReach = 5
+
+// 地域の形。陸地で切り抜いて描く（canvas.beginLand）
+type cell struct {
+	Coordinates [][2]float64 // [経度, 緯度]
+	// Coordinates[i] から次の点までの辺で隣り合う地域（areaList の添字）。外周は -1
+	Neighbors []int
+}
+
+// 各地域の形を代表点のボロノイ領域にする。代表点から最も近い地域に属するものとして境界を引く
+func withCells(areas []area) []area {
+	for i, a := range areas {
+		x, y := a.Longitude*cellCos, a.Latitude
+		r := a.Radius * cellReach
+		c := cell{
+			Coordinates: [][2]float64{{x - r, y - r}, {x + r, y - r}, {x + r, y + r}, {x - r, y + r}},
+			Neighbors:   []int{-1, -1, -1, -1},
+		}
+		for j, b := range areas {
+			if i == j {
+				continue
+			}
+			bx, by := b.Longitude*cellCos, b.Latitude
+			// 十分に離れた代表点とは隣り合わない
+			if math.Abs(bx-x) > 3*r || math.Abs(by-y) > 3*r {
+				continue
+			}
+			c = c.clip(x, y, bx, by, j)
+		}
+		for k := range c.Coordinates {
+			c.Coordinates[k][0] /= cellCos
+		}
+		areas[i].cell = c
+	}
+	return areas
+}
+
+// (x, y) より (bx, by) に近い部分を切り落とす。切り口の辺は neighbor と隣り合う
+func (c cell) clip(x, y, bx, by float64, neighbor int) cell {
+	mx, my := (x+bx)/2, (y+by)/2
+	dx, dy := bx-x, by-y
+	inside := func(p [2]float64) bool { return (p[0]-mx)*dx+(p[1]-my)*dy <= 0 }
+	cross := func(p, q [2]float64) [2]float64 {
+		a := (p[0]-mx)*dx + (p[1]-my)*dy
+		b := (q[0]-mx)*dx + (q[1]-my)*dy
+		t := a / (a - b)
+		return [2]float64{p[0] + (q[0]-p[0])*t, p[1] + (q[1]-p[1])*t}
+	}
+
+	var result cell
+	n := len(c.Coordinates)
+	for k := 0; k < n; k++ {
+		p, q := c.Coordinates[k], c.Coordinates[(k+1)%n]
+		pin, qin := inside(p), inside(q)
+		if pin {
+			result.Coordinates = append(result.Coordinates, p)
+			result.Neighbors = append(result.Neighbors, c.Neighbors[k])
+		}
+		if pin && !qin {
+			result.Coordinates = append(result.Coordinates, cross(p, q))
+			result.Neighbors = append(result.Neighbors, neighbor)
+		}
+		if !pin && qin {
+			result.Coordinates = append(result.Coordinates, cross(p, q))
+			result.Neighbors = append(result.Neighbors, c.Neighbors[k])
+		}
+	}
+	return result
+}
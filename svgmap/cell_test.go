@@ -0,0 +1,94 @@
+package svgmap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCellClip(t *testing.T) {
+	square := cell{
+		Coordinates: [][2]float64{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+		Neighbors:   []int{-1, -1, -1, -1},
+	}
+
+	// (1, 2) と (3, 2) の垂直二等分線 x = 2 で右側を切り落とす
+	got := square.clip(1, 2, 3, 2, 7)
+	want := cell{
+		Coordinates: [][2]float64{{0, 0}, {2, 0}, {2, 4}, {0, 4}},
+		Neighbors:   []int{-1, 7, -1, -1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("clip() = %+v, want %+v", got, want)
+	}
+
+	// 二等分線が外にあれば変わらない
+	if got := square.clip(1, 2, 9, 2, 7); !reflect.DeepEqual(got, square) {
+		t.Errorf("clip() = %+v, want %+v", got, square)
+	}
+}
+
+// 凸多角形の内側（辺上を含む）か
+func insideCell(c cell, x, y float64) bool {
+	n := len(c.Coordinates)
+	sign := 0.0
+	for k := 0; k < n; k++ {
+		p, q := c.Coordinates[k], c.Coordinates[(k+1)%n]
+		cross := (q[0]-p[0])*(y-p[1]) - (q[1]-p[1])*(x-p[0])
+		if cross*sign < -1e-9 {
+			return false
+		}
+		if cross != 0 {
+			sign = cross
+		}
+	}
+	return true
+}
+
+func TestWithCells(t *testing.T) {
+	areas := withCells([]area{
+		{Code: "a", Latitude: 35, Longitude: 135, Radius: 0.2},
+		{Code: "b", Latitude: 35, Longitude: 135.5, Radius: 0.2},
+		// 遠く離れた島
+		{Code: "c", Latitude: 30, Longitude: 130, Radius: 0.2},
+	})
+
+	// 隣り合う地域は二等分線で分かれる
+	mid := 135.25
+	for _, p := range areas[0].cell.Coordinates {
+		if p[0] > mid+1e-9 {
+			t.Errorf("cell a reaches %v beyond the bisector %v", p, mid)
+		}
+	}
+	if !reflect.DeepEqual(neighbors(areas[0].cell), []int{1}) || !reflect.DeepEqual(neighbors(areas[1].cell), []int{0}) {
+		t.Errorf("neighbors = %v, %v, want [1], [0]", neighbors(areas[0].cell), neighbors(areas[1].cell))
+	}
+
+	// 離れた地域は代表点の周りだけに収まる
+	r := 0.2 * cellReach
+	for _, p := range areas[2].cell.Coordinates {
+		if p[1] < 30-r-1e-9 || p[1] > 30+r+1e-9 {
+			t.Errorf("cell c reaches %v", p)
+		}
+	}
+	if len(neighbors(areas[2].cell)) != 0 {
+		t.Errorf("cell c has neighbors %v", neighbors(areas[2].cell))
+	}
+}
+
+func neighbors(c cell) []int {
+	var result []int
+	for _, n := range c.Neighbors {
+		if n >= 0 {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+func TestAreaCells(t *testing.T) {
+	for _, a := range areaList {
+		if !insideCell(a.cell, a.Longitude, a.Latitude) {
+			t.Errorf("area %s: the representative point is outside its cell", a.Code)
+		}
+	}
+}
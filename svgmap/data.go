@@ -0,0 +1,122 @@
+package svgmap
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+)
+
+type ring struct {
+	Name        string       `json:"name"`
+	Coordinates [][2]float64 `json:"coordinates"` // [経度, 緯度]
+	bounds      Bounds
+}
+
+// 地震感知情報の地域。実際の境界ではなく、代表点のボロノイ領域を陸地で切り抜いた形で近似する
+type area struct {
+	Code      string  `json:"code"`
+	Pref      string  `json:"pref"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Radius    float64 `json:"radius"` // 度。形が広がる範囲の目安
+	cell      cell
+}
+
+// 津波予報区の海岸線
+type tsunamiArea struct {
+	Name  string         `json:"name"`
+	Lines [][][2]float64 `json:"lines"` // [経度, 緯度] の折れ線
+}
+
+type location struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// 簡略化した海岸線（主な島のみ）
+//
+//go:embed data/coastline.json
+var coastlineJSON []byte
+
+// 都道府県の代表点（県庁所在地）
+//
+//go:embed data/prefectures.json
+var prefecturesJSON []byte
+
+// 地震感知情報の地域（model の areaMap と同じコード。北海道は "10" のような 2 桁）
+//
+//go:embed data/userquake_areas.json
+var userquakeAreasJSON []byte
+
+// 津波予報区（予報区名で引く）
+//
+//go:embed data/tsunami_areas.json
+var tsunamiAreasJSON []byte
+
+var coastline = mustRings(coastlineJSON)
+
+var tsunamiAreas = mustTsunamiAreas(tsunamiAreasJSON)
+
+// コード順
+var areaList = withCells(mustAreas(userquakeAreasJSON))
+
+var userquakeAreas = areaIndex(areaList)
+
+var prefectures = mustLocations(prefecturesJSON)
+
+func mustRings(b []byte) []ring {
+	var rings []ring
+	if err := json.Unmarshal(b, &rings); err != nil {
+		panic(err)
+	}
+	for i, r := range rings {
+		b := pointBounds(r.Coordinates[0][1], r.Coordinates[0][0])
+		for _, c := range r.Coordinates {
+			b = b.extend(c[1], c[0])
+		}
+		rings[i].bounds = b
+	}
+	return rings
+}
+
+func mustAreas(b []byte) []area {
+	var areas []area
+	if err := json.Unmarshal(b, &areas); err != nil {
+		panic(err)
+	}
+	sort.Slice(areas, func(i, j int) bool { return areas[i].Code < areas[j].Code })
+	return areas
+}
+
+func areaIndex(areas []area) map[string]area {
+	m := make(map[string]area, len(areas))
+	for _, a := range areas {
+		m[a.Code] = a
+	}
+	return m
+}
+
+func mustTsunamiAreas(b []byte) map[string]tsunamiArea {
+	var areas []tsunamiArea
+	if err := json.Unmarshal(b, &areas); err != nil {
+		panic(err)
+	}
+	m := make(map[string]tsunamiArea, len(areas))
+	for _, a := range areas {
+		m[a.Name] = a
+	}
+	return m
+}
+
+func mustLocations(b []byte) map[string]location {
+	var locations []location
+	if err := json.Unmarshal(b, &locations); err != nil {
+		panic(err)
+	}
+	m := make(map[string]location, len(locations))
+	for _, l := range locations {
+		m[l.Name] = l
+	}
+	return m
+}
@@ -0,0 +1,101 @@
+package svgmap
+
+import (
+	"sort"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+const (
+	mapWidth  = 640
+	mapHeight = 480
+)
+
+type scaleMarker struct {
+	location
+	scale int
+}
+
+// 震源と各地の震度を描いた地図。
+// 都道府県の最大震度を観測した地点の座標が分からなければ、その最大震度を代表点に置く
+func Hypocenter(eq *model.Earthquake) []byte {
+	h := eq.HypocenterDetail
+	hasHypocenter := validCoordinate(h.Latitude, h.Longitude)
+	markers := scaleMarkers(eq)
+
+	var bounds Bounds
+	switch {
+	case hasHypocenter:
+		bounds = pointBounds(h.Latitude, h.Longitude)
+		for _, m := range markers {
+			bounds = bounds.extend(m.Latitude, m.Longitude)
+		}
+		bounds = bounds.pad(1, 5)
+	case len(markers) > 0:
+		bounds = pointBounds(markers[0].Latitude, markers[0].Longitude)
+		for _, m := range markers {
+			bounds = bounds.extend(m.Latitude, m.Longitude)
+		}
+		bounds = bounds.pad(1, 5)
+	default:
+		bounds = Japan
+	}
+
+	c := newCanvas(bounds, mapWidth, mapHeight, eq.Title())
+	for _, m := range markers {
+		c.label(m.Latitude, m.Longitude, scaleLabels[m.scale], ScaleColor(m.scale), scaleTextColor(m.scale))
+	}
+	if hasHypocenter {
+		c.cross(h.Latitude, h.Longitude, 9)
+	}
+	return c.bytes()
+}
+
+// 震度の小さい順（大きいものが上に描かれる。「5弱以上と推定」は 5弱 より下）
+func scaleMarkers(eq *model.Earthquake) []scaleMarker {
+	var markers []scaleMarker
+	// 都道府県ごとに、座標の分かる地点で描いた最大の震度
+	located := make(map[string]int)
+	for _, p := range eq.ObservedPoints() {
+		located[p.Pref] = max(located[p.Pref], model.ScaleRank(p.ScaleCode))
+		markers = append(markers, scaleMarker{
+			location: location{Name: p.Name, Latitude: p.Latitude, Longitude: p.Longitude},
+			scale:    p.ScaleCode,
+		})
+	}
+	for pref, scale := range prefMaxScales(eq.Points) {
+		if located[pref] >= model.ScaleRank(scale) {
+			continue
+		}
+		if l, ok := prefectures[pref]; ok {
+			markers = append(markers, scaleMarker{location: l, scale: scale})
+		}
+	}
+	sort.Slice(markers, func(i, j int) bool {
+		if a, b := model.ScaleRank(markers[i].scale), model.ScaleRank(markers[j].scale); a != b {
+			return a < b
+		}
+		return markers[i].Name < markers[j].Name
+	})
+	return markers
+}
+
+// 都道府県ごとの最大震度
+func prefMaxScales(points []model.PointsByPref) map[string]int {
+	scales := make(map[string]int)
+	for _, p := range points {
+		for _, s := range p.Points {
+			if s.ScaleCode <= 0 {
+				continue
+			}
+			if cur, ok := scales[p.Pref]; !ok || model.ScaleRank(s.ScaleCode) > model.ScaleRank(cur) {
+				scales[p.Pref] = s.ScaleCode
+			}
+		}
+	}
+	return scales
+}
+
+func validCoordinate(latitude, longitude float64) bool {
+	return -90 <= latitude && latitude <= 90 && -180 <= longitude && longitude <= 180
+}
@@ -0,0 +1,59 @@
+package svgmap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+func earthquake(lat, lon float64, points ...model.PointsByPref) *model.Earthquake {
+	return &model.Earthquake{
+		IssueType:        "DetailScale",
+		HypocenterDetail: model.Hypocenter{Name: "石川県能登地方", Latitude: lat, Longitude: lon, Depth: 10, Magnitude: 5.6},
+		Points:           points,
+	}
+}
+
+// 座標の分からない観測点（都道府県の代表点に置かれる）
+func unlocated(pref string, scales ...int) model.PointsByPref {
+	p := model.PointsByPref{Pref: pref}
+	for _, s := range scales {
+		p.Points = append(p.Points, model.PointsByScale{ScaleCode: s, Points: []string{pref + "の存在しない観測点"}})
+	}
+	return p
+}
+
+func TestScaleMarkers(t *testing.T) {
+	eq := earthquake(37.5, 137.2, unlocated("石川県", 30, 50), unlocated("富山県", 46), unlocated("新潟県", 45), unlocated("不明", 70))
+
+	var got []string
+	for _, m := range scaleMarkers(eq) {
+		got = append(got, m.Name+scaleLabels[m.scale])
+	}
+	// 小さい順（5弱以上と推定は 5弱 より下）。代表点のない都道府県は描かない
+	want := []string{"富山県5-", "新潟県5-", "石川県5+"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scaleMarkers() = %v, want %v", got, want)
+	}
+}
+
+func TestHypocenter(t *testing.T) {
+	svg := string(Hypocenter(earthquake(37.5, 137.2, unlocated("石川県", 50))))
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>") {
+		t.Fatalf("Hypocenter() = %.60q...", svg)
+	}
+	if !strings.Contains(svg, `stroke="#E00000"`) {
+		t.Error("hypocenter is not drawn")
+	}
+	if !strings.Contains(svg, `fill="`+ScaleColor(50)+`"`) {
+		t.Error("scale marker is not drawn")
+	}
+
+	// 震源が分からなければ × を描かない
+	svg = string(Hypocenter(earthquake(-200, -200, unlocated("石川県", 50))))
+	if strings.Contains(svg, `stroke="#E00000"`) {
+		t.Error("unknown hypocenter is drawn")
+	}
+}
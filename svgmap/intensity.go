@@ -0,0 +1,79 @@
+package svgmap
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+const noObservationColor = "#E6E3DA"
+
+// 凡例に並べる震度
+var legendScales = []int{10, 20, 30, 40, 45, 50, 55, 60, 70}
+
+// 都道府県ごとの最大震度で塗り分けた地図。
+// 都道府県の形は地震感知情報の地域（ボロノイ領域）をまとめたもので近似し、県境だけ線を引く
+func PrefectureIntensity(eq *model.Earthquake) []byte {
+	scales := prefMaxScales(eq.Points)
+
+	bounds := Japan
+	found := false
+	for _, a := range areaList {
+		if _, ok := scales[a.Pref]; !ok {
+			continue
+		}
+		if !found {
+			bounds = pointBounds(a.Latitude, a.Longitude)
+			found = true
+		}
+		bounds = bounds.extend(a.Latitude, a.Longitude)
+	}
+	if found {
+		bounds = bounds.pad(1, 6)
+	}
+
+	c := newCanvas(bounds, mapWidth, mapHeight, "都道府県別の最大震度 "+eq.Title())
+
+	// 同じ都道府県の地域の間に隙間が見えないよう、同じ色の線で縁取る
+	c.beginLand(`stroke-width="0.5" stroke-linejoin="round"`)
+	for _, a := range areaList {
+		fill := noObservationColor
+		if scale, ok := scales[a.Pref]; ok {
+			fill = ScaleColor(scale)
+		}
+		c.polygon(a.cell.Coordinates, fmt.Sprintf(`fill="%s" stroke="%s"`, fill, fill))
+	}
+	c.buf.WriteString(`<g stroke="#FFFFFF" stroke-width="1.2" stroke-linecap="round">`)
+	for _, border := range prefectureBorders() {
+		c.polyline(border[:], "")
+	}
+	c.buf.WriteString("</g>")
+	c.endLand()
+
+	// 凡例
+	for i, scale := range legendScales {
+		x := 16 + float64(i)*22
+		c.box(x+10, mapHeight-19, scaleLabels[scale], ScaleColor(scale), scaleTextColor(scale))
+	}
+	return c.bytes()
+}
+
+// 隣り合う地域の都道府県が異なる辺（県境）。同じ辺を二度描かないよう、添字の小さい側から取る。
+// 半径の違いで片側の領域にしか辺がなければ、その側から取る
+func prefectureBorders() [][2][2]float64 {
+	var borders [][2][2]float64
+	for i, a := range areaList {
+		points := a.cell.Coordinates
+		for k, j := range a.cell.Neighbors {
+			if j < 0 || areaList[j].Pref == a.Pref {
+				continue
+			}
+			if j < i && slices.Contains(areaList[j].cell.Neighbors, i) {
+				continue
+			}
+			borders = append(borders, [2][2]float64{points[k], points[(k+1)%len(points)]})
+		}
+	}
+	return borders
+}
@@ -0,0 +1,81 @@
+package svgmap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+func TestPrefMaxScales(t *testing.T) {
+	got := prefMaxScales([]model.PointsByPref{
+		unlocated("石川県", 30, 50, 46),
+		// 5弱以上と推定は 5弱 より小さい
+		unlocated("富山県", 46, 45),
+		unlocated("新潟県", -1),
+	})
+	want := map[string]int{"石川県": 50, "富山県": 45}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("prefMaxScales() = %v, want %v", got, want)
+	}
+}
+
+func TestPrefectureBorders(t *testing.T) {
+	index := make(map[string]int, len(areaList))
+	for i, a := range areaList {
+		index[a.Code] = i
+	}
+
+	type edge struct{ a, b [2]float64 }
+	seen := make(map[edge]bool)
+	for _, b := range prefectureBorders() {
+		if seen[edge{b[0], b[1]}] || seen[edge{b[1], b[0]}] {
+			t.Errorf("border %v is drawn twice", b)
+		}
+		seen[edge{b[0], b[1]}] = true
+	}
+
+	// 半径の違う地域の間では、片側の領域にしか辺がないことがある（長崎県 630 と山口県 540 など）
+	for _, pair := range [][2]string{{"435", "416"}, {"630", "540"}} {
+		a := areaList[index[pair[0]]]
+		found := false
+		for k, j := range a.cell.Neighbors {
+			if j != index[pair[1]] {
+				continue
+			}
+			p, q := a.cell.Coordinates[k], a.cell.Coordinates[(k+1)%len(a.cell.Coordinates)]
+			found = found || seen[edge{p, q}]
+		}
+		if !found {
+			t.Errorf("border between %s and %s is not drawn", pair[0], pair[1])
+		}
+	}
+}
+
+func TestPrefectureIntensity(t *testing.T) {
+	svg := string(PrefectureIntensity(earthquake(37.5, 137.2, unlocated("石川県", 50))))
+
+	// 石川県の地域は震度 5強 の色、それ以外は観測なしの色で塗る
+	var ishikawa, others int
+	for _, a := range areaList {
+		if a.Pref == "石川県" {
+			ishikawa++
+		} else {
+			others++
+		}
+	}
+	if n := strings.Count(svg, `fill="`+ScaleColor(50)+`" stroke="`+ScaleColor(50)+`"`); n != ishikawa {
+		t.Errorf("%d areas are filled with 5+, want %d", n, ishikawa)
+	}
+	if n := strings.Count(svg, `fill="`+noObservationColor+`"`); n != others {
+		t.Errorf("%d areas are filled as not observed, want %d", n, others)
+	}
+
+	// 凡例
+	for _, scale := range legendScales {
+		if !strings.Contains(svg, `fill="`+ScaleColor(scale)+`" stroke="#000000"`) {
+			t.Errorf("legend of %d is not drawn", scale)
+		}
+	}
+}
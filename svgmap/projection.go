@@ -0,0 +1,71 @@
+package svgmap
+
+import "math"
+
+// 緯度・経度の範囲
+type Bounds struct {
+	West, South, East, North float64
+}
+
+// 日本全体
+var Japan = Bounds{West: 122, South: 24, East: 149, North: 46}
+
+func pointBounds(latitude, longitude float64) Bounds {
+	return Bounds{West: longitude, South: latitude, East: longitude, North: latitude}
+}
+
+func (b Bounds) extend(latitude, longitude float64) Bounds {
+	return Bounds{
+		West:  math.Min(b.West, longitude),
+		South: math.Min(b.South, latitude),
+		East:  math.Max(b.East, longitude),
+		North: math.Max(b.North, latitude),
+	}
+}
+
+// 周囲に margin 度の余白を付け、縦横とも span 度以上にする
+func (b Bounds) pad(margin, span float64) Bounds {
+	b = Bounds{West: b.West - margin, South: b.South - margin, East: b.East + margin, North: b.North + margin}
+	if d := span - (b.East - b.West); d > 0 {
+		b.West, b.East = b.West-d/2, b.East+d/2
+	}
+	if d := span - (b.North - b.South); d > 0 {
+		b.South, b.North = b.South-d/2, b.North+d/2
+	}
+	return b
+}
+
+func (b Bounds) intersects(o Bounds) bool {
+	return b.West <= o.East && o.West <= b.East && b.South <= o.North && o.South <= b.North
+}
+
+// 正距円筒図法を中心緯度で横に縮めたもの。狭い範囲なら十分
+type projection struct {
+	bounds        Bounds // 実際に描く範囲（縦横比に合わせて広げたもの）
+	width, height float64
+	scale, cos    float64
+}
+
+// bounds がすべて収まるように width x height に合わせる
+func newProjection(bounds Bounds, width, height float64) projection {
+	cy := (bounds.South + bounds.North) / 2
+	cx := (bounds.West + bounds.East) / 2
+	cos := math.Cos(cy * math.Pi / 180)
+
+	scale := math.Min(width/(bounds.East-bounds.West), height*cos/(bounds.North-bounds.South))
+	w := width / scale
+	h := height * cos / scale
+	return projection{
+		bounds: Bounds{West: cx - w/2, South: cy - h/2, East: cx + w/2, North: cy + h/2},
+		width:  width,
+		height: height,
+		scale:  scale,
+		cos:    cos,
+	}
+}
+
+func (p projection) point(latitude, longitude float64) (x, y float64) {
+	x = (longitude - p.bounds.West) * p.scale
+	y = (p.bounds.North - latitude) * p.scale / p.cos
+	return x, y
+}
@@ -0,0 +1,78 @@
+package svgmap
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestBoundsPad(t *testing.T) {
+	// 1 点だけなら span 度四方になる
+	b := pointBounds(35, 135).pad(1, 5)
+	if want := (Bounds{West: 132.5, South: 32.5, East: 137.5, North: 37.5}); b != want {
+		t.Errorf("pad() = %+v, want %+v", b, want)
+	}
+
+	// span より広ければ余白だけ付ける
+	b = pointBounds(30, 130).extend(40, 140).pad(1, 5)
+	if want := (Bounds{West: 129, South: 29, East: 141, North: 41}); b != want {
+		t.Errorf("pad() = %+v, want %+v", b, want)
+	}
+}
+
+func TestBoundsIntersects(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Bounds
+		want bool
+	}{
+		{name: "inside", b: Bounds{West: 135, South: 35, East: 136, North: 36}, want: true},
+		{name: "overlapping", b: Bounds{West: 148, South: 45, East: 150, North: 47}, want: true},
+		{name: "touching", b: Bounds{West: 149, South: 30, East: 150, North: 31}, want: true},
+		{name: "outside", b: Bounds{West: 150, South: 30, East: 151, North: 31}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Japan.intersects(tt.b); got != tt.want {
+				t.Errorf("intersects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjection(t *testing.T) {
+	b := Bounds{West: 130, South: 30, East: 140, North: 40}
+	p := newProjection(b, 640, 480)
+
+	// 指定した範囲はすべて収まる
+	for _, c := range [][2]float64{{b.South, b.West}, {b.North, b.East}} {
+		x, y := p.point(c[0], c[1])
+		if x < -1e-6 || x > 640+1e-6 || y < -1e-6 || y > 480+1e-6 {
+			t.Errorf("point(%v) = (%v, %v), outside the canvas", c, x, y)
+		}
+	}
+
+	// 中心は中央に描かれる
+	if x, y := p.point(35, 135); !near(x, 320) || !near(y, 240) {
+		t.Errorf("center = (%v, %v), want (320, 240)", x, y)
+	}
+
+	// 描く範囲の隅は画像の隅になる
+	if x, y := p.point(p.bounds.North, p.bounds.West); !near(x, 0) || !near(y, 0) {
+		t.Errorf("north west = (%v, %v), want (0, 0)", x, y)
+	}
+	if x, y := p.point(p.bounds.South, p.bounds.East); !near(x, 640) || !near(y, 480) {
+		t.Errorf("south east = (%v, %v), want (640, 480)", x, y)
+	}
+
+	// 経度 1 度は中心緯度の cos 倍に縮めた緯度 1 度と同じ長さ
+	x0, y0 := p.point(35, 135)
+	x1, _ := p.point(35, 136)
+	_, y1 := p.point(36, 135)
+	if !near((x1-x0)/(y0-y1), math.Cos(35*math.Pi/180)) {
+		t.Errorf("aspect = %v, want cos(35°)", (x1-x0)/(y0-y1))
+	}
+}
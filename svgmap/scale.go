@@ -0,0 +1,45 @@
+package svgmap
+
+// 震度の色（template/input.css の x-scale と同じ）
+var scaleColors = map[int]string{
+	10: "#A0E0FF",
+	20: "#A0D0FF",
+	30: "#B0C0FF",
+	40: "#70E080",
+	45: "#80C000",
+	46: "#80C000",
+	50: "#F08000",
+	55: "#D07000",
+	60: "#E02020",
+	70: "#A00020",
+}
+
+// 地図上では幅を取らないよう弱・強を -・+ で表す
+var scaleLabels = map[int]string{
+	10: "1",
+	20: "2",
+	30: "3",
+	40: "4",
+	45: "5-",
+	46: "5-",
+	50: "5+",
+	55: "6-",
+	60: "6+",
+	70: "7",
+}
+
+// 震度の色。分からなければ白
+func ScaleColor(code int) string {
+	if c, ok := scaleColors[code]; ok {
+		return c
+	}
+	return "#FFFFFF"
+}
+
+// 色の濃い震度 5 弱以上は白抜きにする
+func scaleTextColor(code int) string {
+	if code >= 45 {
+		return "#FFFFFF"
+	}
+	return "#000000"
+}
@@ -0,0 +1,84 @@
+package svgmap
+
+import (
+	"fmt"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+// 予報の種類の色（template/input.css の x-tsunami と同じ）
+var gradeColors = map[string]string{
+	"MajorWarning": "#C800FF",
+	"Warning":      "#FF2800",
+	"Watch":        "#FAF500",
+	"Unknown":      "#A0A0A0",
+}
+
+// 弱いものから描き、強いものを上に重ねる
+var gradeOrder = []string{"Unknown", "Watch", "Warning", "MajorWarning"}
+
+var gradeLegends = []struct {
+	grade, label string
+}{
+	{"MajorWarning", "大津波警報"},
+	{"Warning", "津波警報"},
+	{"Watch", "津波注意報"},
+	{"Unknown", "予報種類不明"},
+}
+
+// 津波予報区の海岸線を予報の種類で塗り分けた地図
+func Tsunami(t *model.Tsunami) []byte {
+	byGrade := make(map[string][]tsunamiArea)
+	bounds := Japan
+	found := false
+	if !t.Cancelled {
+		for _, g := range t.AreaByGrade {
+			for _, a := range g.Areas {
+				area, ok := tsunamiAreas[a.Name]
+				if !ok {
+					continue
+				}
+				byGrade[g.Grade] = append(byGrade[g.Grade], area)
+				for _, line := range area.Lines {
+					for _, p := range line {
+						if !found {
+							bounds = pointBounds(p[1], p[0])
+							found = true
+						}
+						bounds = bounds.extend(p[1], p[0])
+					}
+				}
+			}
+		}
+	}
+	if found {
+		bounds = bounds.pad(1, 6)
+	}
+
+	title := "津波予報 " + t.IssueTime
+	if t.Cancelled {
+		title = "津波予報 解除 " + t.IssueTime
+	}
+	c := newCanvas(bounds, mapWidth, mapHeight, title)
+
+	for _, grade := range gradeOrder {
+		color, ok := gradeColors[grade]
+		if !ok {
+			continue
+		}
+		for _, area := range byGrade[grade] {
+			for _, line := range area.Lines {
+				c.polyline(line, `stroke="#333333" stroke-width="8" stroke-linecap="round" stroke-linejoin="round"`)
+				c.polyline(line, fmt.Sprintf(`stroke="%s" stroke-width="6" stroke-linecap="round" stroke-linejoin="round"`, color))
+			}
+		}
+	}
+
+	// 凡例
+	for i, l := range gradeLegends {
+		y := float64(mapHeight - 26 - (len(gradeLegends)-1-i)*22)
+		fmt.Fprintf(&c.buf, `<rect x="16" y="%.0f" width="24" height="14" fill="%s" stroke="#333333" stroke-width="0.8"/>`, y, gradeColors[l.grade])
+		c.text(46, y+12, l.label, "#000000", 12, "start")
+	}
+	return c.bytes()
+}
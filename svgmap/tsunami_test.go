@@ -0,0 +1,77 @@
+package svgmap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+func tsunami(cancelled bool, grades map[string][]string) *model.Tsunami {
+	t := &model.Tsunami{IssueTime: "2026/10/01 10:05", Cancelled: cancelled}
+	for _, grade := range gradeOrder {
+		g := model.AreaByGrade{Grade: grade}
+		for _, name := range grades[grade] {
+			g.Areas = append(g.Areas, model.ForecastArea{Name: name, Grade: grade})
+		}
+		if len(g.Areas) > 0 {
+			t.AreaByGrade = append(t.AreaByGrade, g)
+		}
+	}
+	return t
+}
+
+// 予報区の海岸線（凡例を除く）の数
+func countLines(svg, grade string) int {
+	return strings.Count(svg, `stroke="`+gradeColors[grade]+`" stroke-width="6"`)
+}
+
+func TestTsunami(t *testing.T) {
+	svg := string(Tsunami(tsunami(false, map[string][]string{
+		"Warning": {"陸奥湾"},
+		"Watch":   {"オホーツク海沿岸", "存在しない予報区"},
+		"Unknown": {"青森県日本海沿岸"},
+	})))
+
+	for grade, names := range map[string][]string{"Warning": {"陸奥湾"}, "Watch": {"オホーツク海沿岸"}, "Unknown": {"青森県日本海沿岸"}} {
+		want := 0
+		for _, name := range names {
+			want += len(tsunamiAreas[name].Lines)
+		}
+		if got := countLines(svg, grade); got != want {
+			t.Errorf("%s: %d lines, want %d", grade, got, want)
+		}
+	}
+
+	// 強いものほど後に描く
+	if strings.Index(svg, `stroke="`+gradeColors["Warning"]+`" stroke-width="6"`) < strings.Index(svg, `stroke="`+gradeColors["Watch"]+`" stroke-width="6"`) {
+		t.Error("Warning is drawn under Watch")
+	}
+}
+
+func TestTsunamiCancelled(t *testing.T) {
+	svg := string(Tsunami(tsunami(true, map[string][]string{"Watch": {"陸奥湾"}})))
+	if countLines(svg, "Watch") != 0 {
+		t.Error("cancelled areas are drawn")
+	}
+	if !strings.Contains(svg, "<title>津波予報 解除 ") {
+		t.Error("title does not say cancelled")
+	}
+}
+
+func TestTsunamiLegend(t *testing.T) {
+	svg := string(Tsunami(tsunami(false, nil)))
+
+	// 予報の種類が分からないものも凡例に載せる
+	for _, l := range gradeLegends {
+		if _, ok := gradeColors[l.grade]; !ok {
+			t.Errorf("%s has no color", l.grade)
+		}
+		if !strings.Contains(svg, `fill="`+gradeColors[l.grade]+`"`) || !strings.Contains(svg, ">"+l.label+"</text>") {
+			t.Errorf("legend of %s is not drawn", l.grade)
+		}
+	}
+	if len(gradeLegends) != len(gradeOrder) {
+		t.Errorf("%d legends for %d grades", len(gradeLegends), len(gradeOrder))
+	}
+}
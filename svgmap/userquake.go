@@ -0,0 +1,72 @@
+package svgmap
+
+import (
+	"fmt"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+// 信頼度の不透明度（template/input.css の x-confidence と同じ）
+var confidenceOpacities = map[string]float64{
+	"A": 1,
+	"B": 0.8,
+	"C": 0.6,
+	"D": 0.4,
+	"E": 0.2,
+}
+
+const confidenceColor = "#F08000"
+
+// 同じ地震感知情報の時系列をすべて収める範囲。
+// コマ送りしても地図が動かないよう、各コマで同じ範囲を使う
+func UserquakeBounds(uqs []*model.Userquake) Bounds {
+	var bounds Bounds
+	found := false
+	for _, uq := range uqs {
+		for _, a := range uq.Areas {
+			if _, ok := confidenceOpacities[a.Label]; !ok {
+				continue
+			}
+			area, ok := userquakeAreas[a.Code]
+			if !ok {
+				continue
+			}
+			if !found {
+				bounds = pointBounds(area.Latitude, area.Longitude)
+				found = true
+			}
+			bounds = bounds.extend(area.Latitude, area.Longitude)
+		}
+	}
+	if !found {
+		return Japan
+	}
+	return bounds.pad(1, 6)
+}
+
+// 地域ごとの信頼度を塗り分けた地図
+func Userquake(uq *model.Userquake, bounds Bounds) []byte {
+	c := newCanvas(bounds, mapWidth, mapHeight, "「揺れた！」 "+uq.StartTime)
+
+	c.beginLand(`stroke="#FFFFFF" stroke-width="1"`)
+	for _, a := range uq.Areas {
+		opacity, ok := confidenceOpacities[a.Label]
+		if !ok {
+			continue
+		}
+		area, ok := userquakeAreas[a.Code]
+		if !ok {
+			continue
+		}
+		c.polygon(area.cell.Coordinates, fmt.Sprintf(`fill="%s" fill-opacity="%.1f"`, confidenceColor, opacity))
+	}
+	c.endLand()
+
+	// 凡例
+	for i, label := range []string{"A", "B", "C", "D", "E"} {
+		x := 16 + float64(i)*22
+		fmt.Fprintf(&c.buf, `<rect x="%.0f" y="%d" width="20" height="18" fill="%s" fill-opacity="%.1f" stroke="#000000" stroke-width="0.8"/>`, x, mapHeight-28, confidenceColor, confidenceOpacities[label])
+		c.text(x+10, mapHeight-14.5, label, "#000000", 12, "middle")
+	}
+	return c.bytes()
+}
@@ -0,0 +1,50 @@
+package svgmap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/p2pquake/web-client/model"
+)
+
+func TestUserquakeAreas(t *testing.T) {
+	// model の areaMap と同じく北海道は 2 桁
+	for _, code := range []string{"10", "75", "100", "680"} {
+		if _, ok := userquakeAreas[code]; !ok {
+			t.Errorf("area %s is not found", code)
+		}
+	}
+	if _, ok := userquakeAreas["010"]; ok {
+		t.Error("area 010 is found")
+	}
+}
+
+func TestUserquakeBounds(t *testing.T) {
+	if got := UserquakeBounds(nil); got != Japan {
+		t.Errorf("UserquakeBounds(nil) = %+v, want Japan", got)
+	}
+
+	// 信頼度の低い地域（F）は範囲に含めない
+	uqs := []*model.Userquake{
+		{Areas: []model.UserquakeArea{{Code: "10", Label: "A"}, {Code: "680", Label: "F"}}},
+		{Areas: []model.UserquakeArea{{Code: "15", Label: "C"}}},
+	}
+	a, b := userquakeAreas["10"], userquakeAreas["15"]
+	want := pointBounds(a.Latitude, a.Longitude).extend(b.Latitude, b.Longitude).pad(1, 6)
+	if got := UserquakeBounds(uqs); got != want {
+		t.Errorf("UserquakeBounds() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUserquake(t *testing.T) {
+	uq := &model.Userquake{StartTime: "2026/10/01 10:10:00", Areas: []model.UserquakeArea{{Code: "10", Label: "B"}, {Code: "999", Label: "A"}}}
+	svg := string(Userquake(uq, UserquakeBounds([]*model.Userquake{uq})))
+
+	// 北海道 石狩だけが塗られる（凡例の 5 つを除く）
+	if n := strings.Count(svg, `fill="`+confidenceColor+`"`) - 5; n != 1 {
+		t.Errorf("%d areas are drawn, want 1", n)
+	}
+	if !strings.Contains(svg, `fill-opacity="0.8"/>`) {
+		t.Error("area is not drawn with the opacity of B")
+	}
+}